@@ -18,6 +18,7 @@ import (
 	"github.com/Seraphli/tg-cli/internal/config"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
 	"github.com/Seraphli/tg-cli/internal/pairing"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -43,7 +44,7 @@ func startTypingLoop(ctx context.Context, bot *tele.Bot) {
 					continue
 				}
 				// Check tmux route first
-				if chatID, ok := creds.RouteMap[info.tmuxTarget]; ok {
+				if chatID, _, ok := lookupRouteKey(&creds, info.tmuxTarget); ok {
 					if !sentChats[chatID] {
 						bot.Notify(&tele.Chat{ID: chatID}, tele.Typing)
 						sentChats[chatID] = true
@@ -92,6 +93,7 @@ func startLivenessLoop(ctx context.Context, bot *tele.Bot) {
 					cleanDeadSession(info.tmuxTarget, bot)
 				}
 			}
+			requestOverviewRefresh()
 		}
 	}
 }
@@ -158,6 +160,22 @@ func runBot(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Failed to create bot: %v\n", err)
 		os.Exit(1)
 	}
+	// Route all outbound traffic through the rate-limited queue
+	outbox.start(bot)
+	// Messages in forum topics are not replies unless they quote a message
+	bot.Use(topicReplies)
+	// Handler replies and edits go through the queue as well
+	bot.Use(queuedReplies)
+	// User-defined message templates and the message → pane map used for replies
+	notify.SetTemplateDir(filepath.Join(config.GetConfigDir(), "templates"))
+	msgTargets.load()
+	sessionBases.load()
+	sessionTopics.load()
+	sessionAliases.load()
+	activeSessions.load()
+	migrateRouteKeys()
+	openUsageStore()
+	overviews.load()
 	// Build command list for Telegram menu
 	var commands []tele.Command
 	// Bot's own commands
@@ -172,9 +190,16 @@ func runBot(cmd *cobra.Command, args []string) {
 		tele.Command{Text: "bot_perm_status", Description: "Show current pane content"},
 		tele.Command{Text: "bot_capture", Description: "Capture tmux pane content"},
 		tele.Command{Text: "bot_escape", Description: "Send Escape to interrupt Claude"},
+		tele.Command{Text: "bot_git", Description: "Show git status, diff, log or a commit"},
+		tele.Command{Text: "bot_rules", Description: "Manage saved permission rules"},
+		tele.Command{Text: "bot_pending", Description: "List unanswered requests of all sessions"},
 		tele.Command{Text: "bot_routes", Description: "Show route bindings"},
+		tele.Command{Text: "bot_lang", Description: "Show or set the bot language"},
+		tele.Command{Text: "bot_usage", Description: "Show token and cost usage"},
 		tele.Command{Text: "bot_bind", Description: "Bind a tmux session to this chat"},
 		tele.Command{Text: "bot_unbind", Description: "Unbind a tmux session from this chat"},
+		tele.Command{Text: "bot_alias", Description: "Name a session so routes can follow it"},
+		tele.Command{Text: "bot_use", Description: "Pick the session plain messages go to"},
 		tele.Command{Text: "resume", Description: "Resume a previous Claude Code session"},
 	)
 	// CC built-in commands
@@ -203,6 +228,9 @@ func runBot(cmd *cobra.Command, args []string) {
 	defer typingCancel()
 	go startTypingLoop(typingCtx, bot)
 	go startLivenessLoop(typingCtx, bot)
+	go startUsageReportLoop(typingCtx, bot)
+	go startOverviewLoop(typingCtx, bot)
+	go startPermissionTimeoutLoop(typingCtx, bot)
 	go func() {
 		<-ctx.Done()
 		logger.Info("Received shutdown signal, stopping...")
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,12 +11,23 @@ import (
 	"time"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
 	tele "gopkg.in/telebot.v3"
 )
 
+// apiMsgChat returns the chat of the message an API call refers to: the
+// chat_id parameter or, without one, the chat lookup finds msg_id in.
+func apiMsgChat(r *http.Request, msgID int, lookup func(int) (int64, bool)) int64 {
+	if chatID, err := strconv.ParseInt(r.URL.Query().Get("chat_id"), 10, 64); err == nil {
+		return chatID
+	}
+	chatID, _ := lookup(msgID)
+	return chatID
+}
+
 // registerHTTPAPI registers all HTTP API endpoints
 func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credentials) {
 	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +43,7 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			http.Error(w, "invalid page", 400)
 			return
 		}
-		entry, ok := pages.get(msgID)
+		entry, ok := pages.get(msgKey{apiMsgChat(r, msgID, pages.chatOf), msgID})
 		if !ok {
 			http.Error(w, "page entry not found", 404)
 			return
@@ -47,17 +59,20 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 		} else {
 			text = notify.BuildNotificationText(notify.NotificationData{
 				Event:      entry.event,
+				Subject:    entry.subject,
+				Failed:     entry.failed,
 				Project:    entry.project,
 				CWD:        entry.cwd,
 				Body:       entry.chunks[pageNum-1],
 				TmuxTarget: entry.tmuxTarget,
 				Page:       pageNum,
 				TotalPages: len(entry.chunks),
+				Lang:       chatLang(entry.chatID),
 			})
 		}
 		kb := buildPageKeyboardWithExtra(pageNum, len(entry.chunks), entry.permRows)
 		editMsg := &tele.Message{ID: msgID, Chat: chat}
-		_, err = bot.Edit(editMsg, text, kb)
+		_, err = outbox.edit(editMsg, text, kb)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Callback edit failed: %v", err))
 			http.Error(w, "edit failed: "+err.Error(), 500)
@@ -69,22 +84,22 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 	})
 	mux.HandleFunc("/permission/decide", func(w http.ResponseWriter, r *http.Request) {
 		msgID, _ := strconv.Atoi(r.URL.Query().Get("msg_id"))
+		permChatID := apiMsgChat(r, msgID, pendingPerms.chatOf)
 		decision := r.URL.Query().Get("decision")
 		// Pre-check session liveness before processing the decision
-		if tmuxTarget, ok := pendingPerms.getTarget(msgID); ok && tmuxTarget != "" {
+		if tmuxTarget, ok := pendingPerms.getTarget(permChatID, msgID); ok && tmuxTarget != "" {
 			if !checkSessionAlive(tmuxTarget, bot) {
 				http.Error(w, "session disconnected", 410)
 				return
 			}
 		}
-		uuid, uuidOk := pendingPerms.getUUID(msgID)
+		uuid, uuidOk := pendingPerms.getUUID(permChatID, msgID)
 		if !uuidOk {
-			uuid, uuidOk = pendingFiles.get(msgID)
+			uuid, uuidOk = pendingFiles.get(permChatID, msgID)
 		}
-		msgText := pendingPerms.getMsgText(msgID)
-		permChatID := pendingPerms.getChatID(msgID)
-		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(msgID))
-		d, err := resolvePermission(msgID, decision, nil)
+		msgText := pendingPerms.getMsgText(permChatID, msgID)
+		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(permChatID, msgID), chatLang(permChatID))
+		d, err := resolvePermission(permChatID, msgID, decision, nil)
 		if err != nil {
 			http.Error(w, err.Error(), 404)
 			return
@@ -96,15 +111,19 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 				json.Unmarshal(d.UpdatedPermissions, &perms)
 				updatedPerms = perms
 			}
-			ccOutput := buildPermCCOutput(d.Behavior, d.Message, updatedPerms)
-			if err := writePendingAnswer(uuid, ccOutput); err != nil {
+			ccOutput := buildPermCCOutput(d.Behavior, d.Message, updatedPerms, nil)
+			if err := writePendingAnswer(uuid, msgKey{permChatID, msgID}, ccOutput); errors.Is(err, errAlreadyAnswered) {
+				showAnsweredElsewhere(permChatID, msgID, msgText)
+				http.Error(w, "already answered", http.StatusConflict)
+				return
+			} else if err != nil {
 				logger.Error(fmt.Sprintf("Failed to write pending answer for perm: %v", err))
 			}
 		}
 		logger.Info(fmt.Sprintf("Permission resolved via API: msg_id=%d decision=%s uuid=%s", msgID, decision, uuid))
 		if permChatID != 0 && msgText != "" {
 			editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: permChatID}}
-			bot.Edit(editMsg, msgText, buildFrozenPermMarkup(decision, sugLabels))
+			outbox.edit(editMsg, msgText, buildFrozenPermMarkup(decision, sugLabels, chatLang(permChatID)))
 		}
 		respJSON, _ := json.Marshal(d)
 		w.Header().Set("Content-Type", "application/json")
@@ -112,10 +131,11 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 	})
 	mux.HandleFunc("/tool/respond", func(w http.ResponseWriter, r *http.Request) {
 		msgID, _ := strconv.Atoi(r.URL.Query().Get("msg_id"))
+		chatID := apiMsgChat(r, msgID, toolNotifs.chatOf)
 		tool := r.URL.Query().Get("tool")
 		action := r.URL.Query().Get("action")
 		// Pre-check session liveness before processing the response
-		if entry, ok := toolNotifs.get(msgID); ok && entry.tmuxTarget != "" {
+		if entry, ok := toolNotifs.get(chatID, msgID); ok && entry.tmuxTarget != "" {
 			if !checkSessionAlive(entry.tmuxTarget, bot) {
 				http.Error(w, "session disconnected", 410)
 				return
@@ -125,7 +145,7 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 		case "AskUserQuestion":
 			if action == "text" {
 				value := r.URL.Query().Get("value")
-				entry, ok := toolNotifs.get(msgID)
+				entry, ok := toolNotifs.get(chatID, msgID)
 				if !ok {
 					http.Error(w, "not found", 404)
 					return
@@ -134,12 +154,12 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 					http.Error(w, "already answered", 400)
 					return
 				}
-				uuid, ok := pendingFiles.get(msgID)
+				uuid, ok := pendingFiles.get(chatID, msgID)
 				if !ok {
 					http.Error(w, "pending file not found", 404)
 					return
 				}
-				if handleStalePending(msgID, uuid, bot) {
+				if handleStalePending(chatID, msgID, uuid, bot) {
 					http.Error(w, "hook dead (stale pending)", 410)
 					return
 				}
@@ -154,18 +174,22 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 					answers[entry.questions[0].questionText] = value
 				}
 				ccOutput := buildAskCCOutput(pf.Payload, answers)
-				if err := writePendingAnswer(uuid, ccOutput); err != nil {
+				if err := writePendingAnswer(uuid, msgKey{chatID, msgID}, ccOutput); errors.Is(err, errAlreadyAnswered) {
+					freezePendingMessage(chatID, msgID)
+					http.Error(w, "already answered", http.StatusConflict)
+					return
+				} else if err != nil {
 					logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
 					http.Error(w, "failed to save answer", 500)
 					return
 				}
-				toolNotifs.markResolved(msgID)
+				toolNotifs.markResolved(chatID, msgID)
 				logger.Info(fmt.Sprintf("AskUserQuestion text via API: msg_id=%d uuid=%s text=%s", msgID, uuid, truncateStr(value, 200)))
 				editChat := &tele.Chat{ID: entry.chatID}
 				editMsg := &tele.Message{ID: msgID, Chat: editChat}
-				bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, "✅ Text answer"))
+				outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, i18n.T(chatLang(entry.chatID), "question.text_answer")))
 			} else if action == "submit" {
-				entry, ok := toolNotifs.get(msgID)
+				entry, ok := toolNotifs.get(chatID, msgID)
 				if !ok {
 					http.Error(w, "not found", 404)
 					return
@@ -174,7 +198,7 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 					http.Error(w, "already answered", 400)
 					return
 				}
-				uuid, ok := pendingFiles.get(msgID)
+				uuid, ok := pendingFiles.get(chatID, msgID)
 				if !ok {
 					http.Error(w, "pending file not found", 404)
 					return
@@ -187,20 +211,24 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 				}
 				answers := buildAnswers(entry)
 				ccOutput := buildAskCCOutput(pf.Payload, answers)
-				if err := writePendingAnswer(uuid, ccOutput); err != nil {
+				if err := writePendingAnswer(uuid, msgKey{chatID, msgID}, ccOutput); errors.Is(err, errAlreadyAnswered) {
+					freezePendingMessage(chatID, msgID)
+					http.Error(w, "already answered", http.StatusConflict)
+					return
+				} else if err != nil {
 					logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
 					http.Error(w, "failed to save answer", 500)
 					return
 				}
-				toolNotifs.markResolved(msgID)
+				toolNotifs.markResolved(chatID, msgID)
 				logger.Info(fmt.Sprintf("AskUserQuestion submitted via API: msg_id=%d uuid=%s answers=%v", msgID, uuid, answers))
 				editChat := &tele.Chat{ID: entry.chatID}
 				editMsg := &tele.Message{ID: msgID, Chat: editChat}
-				bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, ""))
+				outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, ""))
 			} else {
 				qIdx, _ := strconv.Atoi(r.URL.Query().Get("question"))
 				optIdx, _ := strconv.Atoi(r.URL.Query().Get("option"))
-				entry, ok := toolNotifs.get(msgID)
+				entry, ok := toolNotifs.get(chatID, msgID)
 				if !ok {
 					http.Error(w, "not found", 404)
 					return
@@ -220,9 +248,10 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 					newMarkup := rebuildAskMarkup(entry)
 					editChat := &tele.Chat{ID: entry.chatID}
 					editMsg := &tele.Message{ID: msgID, Chat: editChat}
-					bot.Edit(editMsg, entry.msgText, newMarkup)
+					outbox.edit(editMsg, entry.msgText, newMarkup)
 				} else {
 					qm.selectedOption = optIdx
+					qm.customAnswer = ""
 					hasSubmit := len(entry.questions) > 1
 					for _, q := range entry.questions {
 						if q.multiSelect {
@@ -230,7 +259,7 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 						}
 					}
 					if !hasSubmit {
-						uuid, ok := pendingFiles.get(msgID)
+						uuid, ok := pendingFiles.get(chatID, msgID)
 						if !ok {
 							http.Error(w, "pending file not found", 404)
 							return
@@ -243,22 +272,26 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 						}
 						answers := buildAnswers(entry)
 						ccOutput := buildAskCCOutput(pf.Payload, answers)
-						if err := writePendingAnswer(uuid, ccOutput); err != nil {
+						if err := writePendingAnswer(uuid, msgKey{chatID, msgID}, ccOutput); errors.Is(err, errAlreadyAnswered) {
+							freezePendingMessage(chatID, msgID)
+							http.Error(w, "already answered", http.StatusConflict)
+							return
+						} else if err != nil {
 							logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
 							http.Error(w, "failed to save answer", 500)
 							return
 						}
-						toolNotifs.markResolved(msgID)
+						toolNotifs.markResolved(chatID, msgID)
 						logger.Info(fmt.Sprintf("AskUserQuestion auto-resolved via API: msg_id=%d uuid=%s q=%d opt=%d label=%s answers=%v", msgID, uuid, qIdx, optIdx, qm.optionLabels[optIdx], answers))
 						editChat := &tele.Chat{ID: entry.chatID}
 						editMsg := &tele.Message{ID: msgID, Chat: editChat}
-						bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, ""))
+						outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, ""))
 					} else {
 						logger.Info(fmt.Sprintf("AskUserQuestion option selected via API: msg_id=%d q=%d opt=%d label=%s", msgID, qIdx, optIdx, qm.optionLabels[optIdx]))
 						newMarkup := rebuildAskMarkup(entry)
 						editChat := &tele.Chat{ID: entry.chatID}
 						editMsg := &tele.Message{ID: msgID, Chat: editChat}
-						bot.Edit(editMsg, entry.msgText, newMarkup)
+						outbox.edit(editMsg, entry.msgText, newMarkup)
 					}
 				}
 			}
@@ -278,7 +311,8 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			TmuxTarget string `json:"tmux_target"`
 			ChatID     int64  `json:"chat_id"`
 			CWD        string `json:"cwd"`
-			Type       string `json:"type"`
+			Type       string `json:"type"` // "tmux" (default), "session" or "project"
+			Key        string `json:"key"`  // a RouteMap key, instead of tmux_target
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -295,14 +329,28 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 				return
 			}
 			creds.ProjectRouteMap[req.CWD] = req.ChatID
+		} else if req.Key == "" {
+			// Bind to where the pane is now, not a cached location
+			refreshPaneLocation(req.TmuxTarget)
+			id := sessionIdentity(req.TmuxTarget)
+			req.Key = id.PaneRouteKey()
+			if req.Type == "session" {
+				req.Key = id.SessionRouteKey()
+			}
+			if req.Key == "" {
+				http.Error(w, "no route key for tmux_target", http.StatusBadRequest)
+				return
+			}
+			unbindRouteKeys(&creds, req.TmuxTarget)
+			creds.RouteMap[req.Key] = req.ChatID
 		} else {
-			creds.RouteMap[req.TmuxTarget] = req.ChatID
+			creds.RouteMap[req.Key] = req.ChatID
 		}
 		if err := config.SaveCredentials(creds); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		logger.Info(fmt.Sprintf("Route bound via API: type=%s tmux=%s cwd=%s → chat=%d", req.Type, req.TmuxTarget, req.CWD, req.ChatID))
+		logger.Info(fmt.Sprintf("Route bound via API: type=%s tmux=%s key=%s cwd=%s → chat=%d", req.Type, req.TmuxTarget, req.Key, req.CWD, req.ChatID))
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"ok":true}`))
 	})
@@ -315,6 +363,7 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			TmuxTarget string `json:"tmux_target"`
 			CWD        string `json:"cwd"`
 			Type       string `json:"type"`
+			Key        string `json:"key"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -327,8 +376,10 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 		}
 		if req.Type == "project" {
 			delete(creds.ProjectRouteMap, req.CWD)
+		} else if req.Key != "" {
+			delete(creds.RouteMap, req.Key)
 		} else {
-			delete(creds.RouteMap, req.TmuxTarget)
+			unbindRouteKeys(&creds, req.TmuxTarget)
 		}
 		if err := config.SaveCredentials(creds); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -344,9 +395,20 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		// routes lists bindings by the pane they currently route; keys that
+		// match no live pane are listed as is. route_keys has the raw keys.
+		routes := make(map[string]int64, len(creds.RouteMap))
+		for key, chatID := range creds.RouteMap {
+			if t := routeKeyTarget(key); t != "" {
+				routes[t] = chatID
+			} else {
+				routes[key] = chatID
+			}
+		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"routes":         creds.RouteMap,
+			"routes":         routes,
+			"route_keys":     creds.RouteMap,
 			"project_routes": creds.ProjectRouteMap,
 		})
 	})
@@ -435,7 +497,10 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 		}
 		// Strip socket prefix so the target matches stored pane IDs
 		target = notify.FormatPaneID(target)
-		msgID, entry, ok := toolNotifs.findByTmuxTarget(target)
+		// chat_id picks the fan-out copy being answered; without it any copy
+		chatID, _ := strconv.ParseInt(r.URL.Query().Get("chat_id"), 10, 64)
+		k, entry, ok := toolNotifs.findByTmuxTarget(chatID, target)
+		chatID, msgID := k.chatID, k.msgID
 		if !ok {
 			// No pending AskUserQuestion — inject text
 			t, err := injector.ParseTarget(target)
@@ -451,12 +516,12 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			fmt.Fprintf(w, "injected")
 			return
 		}
-		uuid, uuidOk := pendingFiles.get(msgID)
+		uuid, uuidOk := pendingFiles.get(chatID, msgID)
 		if !uuidOk {
 			http.Error(w, "pending file not found", 404)
 			return
 		}
-		if handleStalePending(msgID, uuid, bot) {
+		if handleStalePending(chatID, msgID, uuid, bot) {
 			// Stale: hook dead or file missing, inject text instead
 			t, err := injector.ParseTarget(target)
 			if err != nil {
@@ -477,19 +542,23 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			http.Error(w, "failed to read pending file", 500)
 			return
 		}
-		answers := make(map[string]string)
+		answers := buildAnswers(entry)
 		if len(entry.questions) > 0 {
 			answers[entry.questions[0].questionText] = text
 		}
 		ccOutput := buildAskCCOutput(pf.Payload, answers)
-		if err := writePendingAnswer(uuid, ccOutput); err != nil {
+		if err := writePendingAnswer(uuid, k, ccOutput); errors.Is(err, errAlreadyAnswered) {
+			freezePendingMessage(chatID, msgID)
+			http.Error(w, "already answered", http.StatusConflict)
+			return
+		} else if err != nil {
 			http.Error(w, "failed to write answer", 500)
 			return
 		}
-		toolNotifs.markResolved(msgID)
+		toolNotifs.markResolved(chatID, msgID)
 		logger.Info(fmt.Sprintf("AskUserQuestion resolved via group text API: msg_id=%d uuid=%s text=%s", msgID, uuid, truncateStr(text, 200)))
 		editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: entry.chatID}}
-		bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, "✅ Text answer"))
+		outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, i18n.T(chatLang(entry.chatID), "question.text_answer")))
 		fmt.Fprintf(w, "resolved")
 	})
 	mux.HandleFunc("/perm/switch", func(w http.ResponseWriter, r *http.Request) {
@@ -571,29 +640,27 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			http.Error(w, "missing uuid", 400)
 			return
 		}
-		msgID, found := pendingFiles.findByUUID(uuid)
-		if !found {
-			w.WriteHeader(200)
-			return
-		}
-		// Clean up AskUserQuestion state
-		if entry, ok := toolNotifs.get(msgID); ok && !entry.resolved {
-			toolNotifs.markResolved(msgID)
-			editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: entry.chatID}}
-			bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, "❌ Cancelled"))
-			logger.Info(fmt.Sprintf("Pending cancelled via hook signal: uuid=%s msg_id=%d", uuid, msgID))
-		}
-		// Clean up PermissionRequest state — read data BEFORE resolve
-		if _, ok := pendingPerms.getTarget(msgID); ok {
-			permChatID := pendingPerms.getChatID(msgID)
-			permMsgText := pendingPerms.getMsgText(msgID)
-			sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(msgID))
-			pendingPerms.resolve(msgID, permDecision{Behavior: "deny", Message: "Cancelled by user (Esc)"})
-			editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: permChatID}}
-			bot.Edit(editMsg, permMsgText, buildFrozenPermMarkup("❌ Cancelled", sugLabels))
-			logger.Info(fmt.Sprintf("Permission cancelled via hook signal: uuid=%s msg_id=%d", uuid, msgID))
+		// Fan-out copies share the uuid; cancel every message of the request
+		for _, k := range pendingFiles.findByUUID(uuid) {
+			chatID, msgID := k.chatID, k.msgID
+			// Clean up AskUserQuestion state
+			if entry, ok := toolNotifs.get(chatID, msgID); ok && !entry.resolved {
+				toolNotifs.markResolved(chatID, msgID)
+				editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: chatID}}
+				outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, i18n.T(chatLang(chatID), "common.cancelled")))
+				logger.Info(fmt.Sprintf("Pending cancelled via hook signal: uuid=%s msg_id=%d", uuid, msgID))
+			}
+			// Clean up PermissionRequest state — read data BEFORE resolve
+			if _, ok := pendingPerms.getTarget(chatID, msgID); ok {
+				permMsgText := pendingPerms.getMsgText(chatID, msgID)
+				sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(chatID, msgID), chatLang(chatID))
+				pendingPerms.resolve(chatID, msgID, permDecision{Behavior: "deny", Message: "Cancelled by user (Esc)"})
+				editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: chatID}}
+				outbox.edit(editMsg, permMsgText, buildFrozenPermMarkup("cancelled", sugLabels, chatLang(chatID)))
+				logger.Info(fmt.Sprintf("Permission cancelled via hook signal: uuid=%s msg_id=%d", uuid, msgID))
+			}
+			pendingFiles.remove(chatID, msgID)
 		}
-		pendingFiles.remove(msgID)
 		w.WriteHeader(200)
 	})
 	mux.HandleFunc("/mcp/send-file", func(w http.ResponseWriter, r *http.Request) {
@@ -623,13 +690,19 @@ func registerHTTPAPI(mux *http.ServeMux, bot *tele.Bot, creds *config.Credential
 			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": fmt.Sprintf("file too large: %d bytes (max 50MB for Telegram Bot API)", info.Size())})
 			return
 		}
-		chat, _ := resolveChat("", req.CWD)
+		chat, _ := resolveChat("", req.CWD, "")
+		if chat == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "no chat configured for this project"})
+			return
+		}
 		doc := &tele.Document{
 			File:     tele.FromDisk(req.FilePath),
 			FileName: filepath.Base(req.FilePath),
 			Caption:  req.Caption,
 		}
-		msg, err := bot.Send(chat, doc)
+		msg, err := outbox.send(chat, doc)
 		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": fmt.Sprintf("telegram send failed: %v", err)})
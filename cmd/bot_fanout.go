@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/route"
+	tele "gopkg.in/telebot.v3"
+)
+
+// fanOutChats returns the chats other than primary that the fan-out routes
+// copy a session's event to.
+func fanOutChats(tmuxTarget, cwd, env, event string, primary int64) []int64 {
+	creds, err := config.LoadCredentials()
+	if err != nil || len(creds.FanOut) == 0 {
+		return nil
+	}
+	keys := sessionIdentity(tmuxTarget).Keys()
+	return route.FanOut(creds.FanOut, routeSession(tmuxTarget, cwd, env), keys, event, primary)
+}
+
+// eventChats returns the chats an event of the session in p goes to: its
+// routed chat, if any, followed by its fan-out copies, which get a session
+// topic like the routed chat.
+func eventChats(bot *tele.Bot, chat *tele.Chat, p *hookPayload, event string) []*tele.Chat {
+	var chats []*tele.Chat
+	var primary int64
+	if chat != nil {
+		chats = append(chats, chat)
+		primary = chat.ID
+	}
+	for _, id := range fanOutChats(p.TmuxTarget, p.CWD, p.Route, event, primary) {
+		c := &tele.Chat{ID: id}
+		if event != "SessionEnd" {
+			ensureSessionTopic(bot, c, p)
+		}
+		chats = append(chats, c)
+		logger.Info(fmt.Sprintf("Fan-out: %s tmux=%s → chat=%d", event, p.TmuxTarget, id))
+	}
+	return chats
+}
+
+// recordPendingMessage records a message sent for a pending request: the
+// first one as the request's own message, later ones as fan-out copies. It
+// returns false when the request was answered before the copy went out.
+func recordPendingMessage(path string, pf *PendingFile, chatID int64, msgID int) bool {
+	pendingAnswerMu.Lock()
+	defer pendingAnswerMu.Unlock()
+	if pf.TgMsgID == 0 {
+		pf.Status = "sent"
+		pf.TgMsgID = msgID
+		pf.TgChatID = chatID
+		return writePendingFile(path, pf) == nil
+	}
+	cur, err := readPendingFile(path)
+	if err != nil || cur.Status != "sent" {
+		return false
+	}
+	cur.Copies = append(cur.Copies, PendingCopy{ChatID: chatID, MsgID: msgID})
+	pf.Copies = cur.Copies
+	return writePendingFile(path, cur) == nil
+}
+
+// freezeSiblings freezes the messages of an answered request other than
+// from, the one it was answered on, so nobody answers it twice.
+func freezeSiblings(pf *PendingFile, from msgKey) {
+	for _, m := range pf.messages() {
+		if (msgKey{m.ChatID, m.MsgID}) != from {
+			freezePendingMessage(m.ChatID, m.MsgID)
+		}
+	}
+}
+
+// freezePendingMessage drops the buttons of a request's message answered in
+// another chat and forgets its state.
+func freezePendingMessage(chatID int64, msgID int) {
+	text := pendingPerms.getMsgText(chatID, msgID)
+	if entry, ok := toolNotifs.get(chatID, msgID); ok {
+		if text == "" {
+			text = entry.msgText
+		}
+		toolNotifs.markResolved(chatID, msgID)
+	}
+	pendingPerms.cleanup(chatID, msgID)
+	pendingFiles.remove(chatID, msgID)
+	requestOverviewRefresh()
+	showAnsweredElsewhere(chatID, msgID, text)
+	logger.Info(fmt.Sprintf("Fan-out copy frozen: chat=%d msg_id=%d", chatID, msgID))
+}
+
+// showAnsweredElsewhere drops the buttons of a request's message, text if
+// known, and notes that the request was answered in another chat.
+func showAnsweredElsewhere(chatID int64, msgID int, text string) {
+	msg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: chatID}}
+	notice := i18n.T(chatLang(chatID), "perm.elsewhere")
+	if text != "" {
+		outbox.edit(msg, text+"\n\n"+notice)
+	} else {
+		outbox.edit(msg, &tele.ReplyMarkup{})
+		outbox.send(msg.Chat, notice, &tele.SendOptions{ReplyTo: msg})
+	}
+}
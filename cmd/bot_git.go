@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/injector"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	gitLogDefault = 20
+	gitLogMax     = 200
+	// gitMaxPages is the most pages /bot_git paginates; longer output is sent as a file.
+	gitMaxPages = 10
+	// gitPagesTTL is how long the pages of output for a pane without a
+	// tracked session can be turned.
+	gitPagesTTL = time.Hour
+)
+
+// gitAllowedFlags lists the only options each /bot_git subcommand accepts.
+// Everything else starting with "-" is rejected so options that write files
+// (e.g. --output) can't be passed through.
+var gitAllowedFlags = map[string]map[string]bool{
+	"diff": {"--staged": true, "--cached": true, "--stat": true, "--": true},
+}
+
+// gitOutput runs a read-only git command in dir. Optional locks are off so
+// status never refreshes the index, and fsmonitor hooks are disabled.
+func gitOutput(dir string, args ...string) (string, error) {
+	return gitOutputEnv(dir, nil, args...)
+}
+
+// gitOutputEnv is gitOutput with extra environment variables.
+func gitOutputEnv(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "core.fsmonitor=false"}, args...)...)
+	cmd.Env = append(append(os.Environ(), "GIT_OPTIONAL_LOCKS=0"), env...)
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// gitErrorText returns git's own error message when it printed one.
+func gitErrorText(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return err.Error()
+}
+
+// isBotCommand reports whether text invokes cmd, with or without arguments
+// or a @botname suffix.
+func isBotCommand(text, cmd string) bool {
+	return text == cmd || strings.HasPrefix(text, cmd+" ") || strings.HasPrefix(text, cmd+"@")
+}
+
+// targetCWD returns the working directory of the session in the pane,
+// falling back to the pane's current path.
+func targetCWD(target injector.TmuxTarget) string {
+	if info := sessionState.findInfoByTarget(injector.FormatTarget(target)); info != nil && info.cwd != "" {
+		return info.cwd
+	}
+	if path, err := injector.GetPanePath(target); err == nil {
+		return path
+	}
+	return ""
+}
+
+// handleGitCommand handles /bot_git status|diff|log|show in the session's
+// working directory. Only read-only git commands are run.
+func handleGitCommand(c tele.Context, target injector.TmuxTarget) error {
+	args := strings.Fields(c.Message().Text)[1:]
+	if len(args) == 0 {
+		return c.Reply(tr(c, "git.help"))
+	}
+	sub, rest := args[0], args[1:]
+	for _, a := range rest {
+		if strings.HasPrefix(a, "-") && !gitAllowedFlags[sub][a] {
+			return c.Reply(tr(c, "git.bad_arg", a))
+		}
+	}
+	var gitArgs []string
+	switch sub {
+	case "status":
+		if len(rest) > 0 {
+			return c.Reply(tr(c, "git.help"))
+		}
+		gitArgs = []string{"status", "--short", "--branch"}
+	case "diff":
+		gitArgs = append([]string{"diff", "--no-color", "--no-ext-diff", "--no-textconv"}, rest...)
+	case "log":
+		n := gitLogDefault
+		if len(rest) > 1 {
+			return c.Reply(tr(c, "git.help"))
+		}
+		if len(rest) == 1 {
+			v, err := strconv.Atoi(rest[0])
+			if err != nil || v < 1 {
+				return c.Reply(tr(c, "git.help"))
+			}
+			n = min(v, gitLogMax)
+		}
+		gitArgs = []string{"log", "--no-color", "--oneline", "--decorate", "-n", strconv.Itoa(n)}
+	case "show":
+		if len(rest) != 1 {
+			return c.Reply(tr(c, "git.help"))
+		}
+		gitArgs = []string{"show", "--no-color", "--no-ext-diff", "--no-textconv", "--stat", "--patch", rest[0]}
+	default:
+		return c.Reply(tr(c, "git.help"))
+	}
+	cwd := targetCWD(target)
+	if cwd == "" {
+		return c.Reply(tr(c, "git.no_cwd"))
+	}
+	out, err := gitOutput(cwd, gitArgs...)
+	if err != nil {
+		logger.Info(fmt.Sprintf("git %s failed: cwd=%s err=%v", sub, cwd, err))
+		return c.Reply(tr(c, "git.failed", sub, gitErrorText(err)))
+	}
+	logger.Info(fmt.Sprintf("git %s: cwd=%s args=%v bytes=%d", sub, cwd, rest, len(out)))
+	lang := chatLang(c.Chat().ID)
+	tmuxTarget := injector.FormatTarget(target)
+	header := tr(c, "git.title", sub, notify.CompressPath(cwd)) + "\n📟 " + notify.FormatPaneID(tmuxTarget)
+
+	// Diffs are rendered file by file like Edit permission requests
+	body := strings.TrimRight(out, "\n")
+	truncated := false
+	if idx := strings.Index(out, "diff --git "); idx != -1 && (sub == "diff" || sub == "show") {
+		var blocks []string
+		blocks, truncated = notify.BuildGitDiffBlocks(out[idx:], lang)
+		if preface := strings.TrimRight(out[:idx], "\n"); preface != "" {
+			blocks = append([]string{preface}, blocks...)
+		}
+		body = strings.Join(blocks, "\n\n")
+	}
+	if body == "" {
+		body = tr(c, "git.empty")
+	}
+
+	chunks := splitBody(body, 3900-len([]rune(header)))
+	if len(chunks) > gitMaxPages {
+		return sendGitFile(c, sub, header+"\n"+tr(c, "git.too_long"), out)
+	}
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = header + "\n\n" + chunk
+	}
+	opts := &tele.SendOptions{ReplyTo: c.Message()}
+	if len(texts) > 1 {
+		opts.ReplyMarkup = buildPageKeyboard(1, len(texts))
+	}
+	sent, err := outbox.send(c.Chat(), texts[0], opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send git %s output: %v", sub, err))
+		return nil
+	}
+	msgTargets.record(c.Chat().ID, sent.ID, tmuxTarget)
+	if len(texts) > 1 {
+		entry := &pageEntry{chunks: texts, raw: true, tmuxTarget: tmuxTarget, chatID: c.Chat().ID}
+		// Pages go with the pane's session, or expire if it has none
+		if sid, ok := sessionState.findByTarget(tmuxTarget); ok {
+			pages.store(msgKey{c.Chat().ID, sent.ID}, sid, entry)
+		} else {
+			pages.storeFor(msgKey{c.Chat().ID, sent.ID}, gitPagesTTL, entry)
+		}
+	}
+	if truncated {
+		return sendGitFile(c, sub, header, out)
+	}
+	return nil
+}
+
+// sendGitFile sends the full git output as a document replying to the command.
+func sendGitFile(c tele.Context, sub, caption, out string) error {
+	ext := ".txt"
+	if sub == "diff" || sub == "show" {
+		ext = ".diff"
+	}
+	tmp, err := os.CreateTemp("", "tg-cli-git-*"+ext)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create git output file: %v", err))
+		return c.Reply(tr(c, "err.generic", err))
+	}
+	defer os.Remove(tmp.Name())
+	tmp.WriteString(out)
+	tmp.Close()
+	doc := &tele.Document{
+		File:     tele.FromDisk(tmp.Name()),
+		FileName: "git-" + sub + ext,
+		Caption:  caption,
+	}
+	if _, err := outbox.send(c.Chat(), doc, &tele.SendOptions{ReplyTo: c.Message()}); err != nil {
+		logger.Error(fmt.Sprintf("Failed to send git %s file: %v", sub, err))
+	}
+	return nil
+}
@@ -3,15 +3,19 @@ package cmd
 import (
 	"fmt"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
 	"github.com/Seraphli/tg-cli/internal/pairing"
+	"github.com/Seraphli/tg-cli/internal/route"
 	tele "gopkg.in/telebot.v3"
 )
 
@@ -19,6 +23,8 @@ var bindPending sync.Map // msgID (int) -> bindPendingInfo
 
 type bindPendingInfo struct {
 	tmuxTarget string
+	paneKey    string // RouteMap key of the pane
+	sessionKey string // RouteMap key of the session's alias or resume chain
 	cwd        string
 	chatID     int64
 }
@@ -51,42 +57,43 @@ func registerTGHandlers(bot *tele.Bot, creds *config.Credentials) {
 		bot.Handle("/"+tg, func(c tele.Context) error {
 			if c.Message().ReplyTo == nil {
 				if c.Chat().Type == "group" || c.Chat().Type == "supergroup" {
-					tmuxStr, target, err := resolveGroupTarget(c.Chat().ID)
+					tmuxStr, target, err := resolveGroupTarget(c.Message())
 					if err != nil {
 						if err.Error() == "no targets bound" {
-							return c.Send("💡 Please reply to a notification message to target a session.")
+							return c.Send(tr(c, "err.reply_to_target"))
 						}
 						if err.Error() == "multiple sessions bound" {
-							return c.Reply("❌ Multiple sessions bound to this group. Reply to a specific notification.")
+							return c.Reply(tr(c, "err.multiple_sessions"))
 						}
-						return c.Reply("❌ tmux session not found.")
+						return c.Reply(tr(c, "err.session_not_found"))
 					}
 					text := "/" + cc
 					if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
 						text += " " + payload
 					}
 					if err := injector.InjectText(target, text); err != nil {
-						return c.Reply(fmt.Sprintf("❌ Injection failed: %v", err))
+						return c.Reply(tr(c, "err.inject_failed", err))
 					}
 					logger.Info(fmt.Sprintf("Group quick reply (command): target=%s text=%s", tmuxStr, truncateStr(text, 200)))
 					reactAndTrack(bot, c.Message().Chat, c.Message(), tmuxStr)
+					replyActiveNote(c, tmuxStr)
 					return nil
 				}
-				return c.Send("💡 Please reply to a notification message to target a session.")
+				return c.Send(tr(c, "err.reply_to_target"))
 			}
-			target, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+			target, err := resolveReplyTarget(c.Message().ReplyTo)
 			if err != nil {
 				if err.Error() == "no target found" {
-					return c.Send("❌ No tmux session info found in the original message.")
+					return c.Send(tr(c, "err.no_target_in_msg"))
 				}
-				return c.Send("❌ tmux session not found. The Claude Code session may have ended.")
+				return c.Send(tr(c, "err.session_ended"))
 			}
 			text := "/" + cc
 			if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
 				text += " " + payload
 			}
 			if err := injector.InjectText(target, text); err != nil {
-				return c.Send(fmt.Sprintf("❌ Injection failed: %v", err))
+				return c.Send(tr(c, "err.inject_failed", err))
 			}
 			tmuxStr := injector.FormatTarget(target)
 			reactAndTrack(bot, c.Message().Chat, c.Message(), tmuxStr)
@@ -100,38 +107,39 @@ func registerTGHandlers(bot *tele.Bot, creds *config.Credentials) {
 		var target injector.TmuxTarget
 		var tmuxStr string
 		if c.Message().ReplyTo != nil {
-			t, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+			t, err := resolveReplyTarget(c.Message().ReplyTo)
 			if err != nil {
 				if err.Error() == "no target found" {
-					return c.Send("❌ No tmux session info found in the original message.")
+					return c.Send(tr(c, "err.no_target_in_msg"))
 				}
-				return c.Send("❌ tmux session not found. The Claude Code session may have ended.")
+				return c.Send(tr(c, "err.session_ended"))
 			}
 			target = t
 			tmuxStr = injector.FormatTarget(t)
 		} else if c.Chat().Type == "group" || c.Chat().Type == "supergroup" {
-			ts, t, err := resolveGroupTarget(c.Chat().ID)
+			ts, t, err := resolveGroupTarget(c.Message())
 			if err != nil {
 				if err.Error() == "no targets bound" {
-					return c.Send("💡 Please reply to a notification message to target a session.")
+					return c.Send(tr(c, "err.reply_to_target"))
 				}
 				if err.Error() == "multiple sessions bound" {
-					return c.Reply("❌ Multiple sessions bound to this group. Reply to a specific notification.")
+					return c.Reply(tr(c, "err.multiple_sessions"))
 				}
-				return c.Reply("❌ tmux session not found.")
+				return c.Reply(tr(c, "err.session_not_found"))
 			}
 			target = t
 			tmuxStr = ts
 			logger.Debug(fmt.Sprintf("/resume: resolved tmuxStr=%s", tmuxStr))
 		} else {
-			return c.Send("💡 Please reply to a notification message to target a session.")
+			return c.Send(tr(c, "err.reply_to_target"))
 		}
 		// With payload: inject /resume <payload> directly
 		if payload != "" {
 			if err := injector.InjectText(target, "/resume "+payload); err != nil {
-				return c.Send(fmt.Sprintf("❌ Injection failed: %v", err))
+				return c.Send(tr(c, "err.inject_failed", err))
 			}
 			reactAndTrack(bot, c.Message().Chat, c.Message(), tmuxStr)
+			replyActiveNote(c, tmuxStr)
 			return nil
 		}
 		// Without payload: show session picker
@@ -149,17 +157,18 @@ func registerTGHandlers(bot *tele.Bot, creds *config.Credentials) {
 			logger.Debug(fmt.Sprintf("/resume: tmux fallback cwd=%s", cwd))
 		}
 		if cwd == "" {
-			return c.Send("❌ No working directory info available for this session.")
+			return c.Send(tr(c, "resume.no_cwd"))
 		}
 		currentSID, _ := sessionState.findByTarget(tmuxStr)
 		sessions, err := listProjectSessions(cwd, 8, currentSID)
 		if err != nil || len(sessions) == 0 {
-			return c.Send("📂 No previous sessions found for this project.")
+			return c.Send(tr(c, "resume.none"))
 		}
 		if len(sessions) == 0 {
-			return c.Send("📂 No other sessions found for this project.")
+			return c.Send(tr(c, "resume.none_other"))
 		}
-		kb := buildResumeKeyboard(sessions)
+		lang := ctxLang(c)
+		kb := buildResumeKeyboard(sessions, lang)
 		var lines []string
 		lines = append(lines, "📟 "+notify.FormatPaneID(tmuxStr))
 		lines = append(lines, "")
@@ -168,156 +177,257 @@ func registerTGHandlers(bot *tele.Bot, creds *config.Credentials) {
 			if s.SummarySource == "user" {
 				prefix = "👤"
 			}
-			lines = append(lines, fmt.Sprintf("%d. %s %s — %s", i+1, prefix, truncateStr(s.Summary, 500), relativeTime(s.Modified)))
+			lines = append(lines, fmt.Sprintf("%d. %s %s — %s", i+1, prefix, truncateStr(s.Summary, 500), relativeTime(s.Modified, lang)))
 		}
 		text := strings.Join(lines, "\n")
-		_, err = bot.Send(c.Chat(), text, kb)
+		_, err = outbox.send(c.Chat(), text, kb)
 		if err != nil {
-			return c.Send(fmt.Sprintf("❌ Failed to send: %v", err))
+			return c.Send(tr(c, "err.send_failed", err))
 		}
 		return nil
 	})
 
 	bot.Handle("/start", func(c tele.Context) error {
-		return c.Send("tg-cli bot is running. Use /bot_pair to pair this chat.")
+		return c.Send(tr(c, "start.welcome"))
 	})
 
 	bot.Handle("/bot_pair", func(c tele.Context) error {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		chatID := strconv.FormatInt(c.Chat().ID, 10)
 		if pairing.IsAllowed(userID) || pairing.IsAllowed(chatID) {
-			return c.Send("Already paired.")
+			return c.Send(tr(c, "pair.already"))
 		}
 		code := pairing.CreatePairingRequest(userID, chatID)
-		return c.Send(fmt.Sprintf("Pairing code: %s\n\nEnter this code in the bot terminal to approve.\n\nCode expires in 10 minutes.", code))
+		return c.Send(tr(c, "pair.code", code))
 	})
 
 	bot.Handle("/status", func(c tele.Context) error {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		chatID := strconv.FormatInt(c.Chat().ID, 10)
 		if !pairing.IsAllowed(userID) && !pairing.IsAllowed(chatID) {
-			return c.Send("Not paired. Use /bot_pair first.")
+			return c.Send(tr(c, "status.unpaired"))
 		}
-		return c.Send("Bot is running and paired.")
+		return c.Send(tr(c, "status.ok"))
+	})
+
+	bot.Handle("/bot_lang", func(c tele.Context) error {
+		userID := strconv.FormatInt(c.Sender().ID, 10)
+		chatID := strconv.FormatInt(c.Chat().ID, 10)
+		if !pairing.IsAllowed(userID) && !pairing.IsAllowed(chatID) {
+			return c.Send(tr(c, "err.not_paired"))
+		}
+		arg := strings.ToLower(strings.TrimSpace(c.Message().Payload))
+		if arg == "" {
+			return c.Reply(tr(c, "lang.current", ctxLang(c), strings.Join(i18n.Languages(), ", ")))
+		}
+		creds, err := config.LoadCredentials()
+		if err != nil {
+			return c.Reply(tr(c, "err.load_config", err))
+		}
+		// In a private chat the chat ID is the user ID, so this is also the user's setting
+		if arg == "auto" {
+			delete(creds.Languages, chatID)
+		} else {
+			lang := i18n.Normalize(arg)
+			if lang == "" {
+				return c.Reply(tr(c, "lang.unknown", arg))
+			}
+			creds.Languages[chatID] = lang
+		}
+		if err := config.SaveCredentials(creds); err != nil {
+			return c.Reply(tr(c, "err.save", err))
+		}
+		logger.Info(fmt.Sprintf("Language set: chat=%s lang=%s by user=%s", chatID, arg, userID))
+		if arg == "auto" {
+			return c.Reply(tr(c, "lang.auto"))
+		}
+		return c.Reply(tr(c, "lang.set", creds.Languages[chatID]))
+	})
+
+	bot.Handle("/bot_usage", func(c tele.Context) error {
+		userID := strconv.FormatInt(c.Sender().ID, 10)
+		if !pairing.IsAllowed(userID) {
+			return c.Send(tr(c, "err.not_paired"))
+		}
+		periods := []string{"today", "week", "month"}
+		if arg := strings.TrimSpace(c.Message().Payload); arg != "" {
+			if _, _, ok := usagePeriod(arg, time.Now()); !ok {
+				return c.Reply(tr(c, "usage.help"))
+			}
+			periods = []string{arg}
+		}
+		return c.Send(buildUsageText(ctxLang(c), "", periods))
+	})
+
+	bot.Handle("/bot_pending", func(c tele.Context) error {
+		userID := strconv.FormatInt(c.Sender().ID, 10)
+		if !pairing.IsAllowed(userID) {
+			return c.Send(tr(c, "err.not_paired"))
+		}
+		return handlePendingCommand(c)
 	})
 
 	bot.Handle("/bot_routes", func(c tele.Context) error {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		if !pairing.IsAllowed(userID) {
-			return c.Send("❌ Not paired. Use /bot_pair first.")
+			return c.Send(tr(c, "err.not_paired"))
 		}
 		creds, _ := config.LoadCredentials()
-		if len(creds.RouteMap) == 0 && len(creds.ProjectRouteMap) == 0 {
-			return c.Send("No active route bindings.")
+		if len(creds.RouteMap) == 0 && len(creds.ProjectRouteMap) == 0 && len(creds.RouteRules) == 0 && len(creds.FanOut) == 0 {
+			return c.Send(tr(c, "routes.none"))
 		}
-		var lines []string
-		for tmux, chatID := range creds.RouteMap {
-			chatName := fmt.Sprintf("%d", chatID)
+		chatTitle := func(chatID int64) string {
 			if chat, err := bot.ChatByID(chatID); err == nil && chat.Title != "" {
-				chatName = chat.Title
+				return chat.Title
 			}
-			paneID := tmux
-			if idx := strings.Index(paneID, "@"); idx != -1 {
-				paneID = paneID[:idx]
-			}
-			lines = append(lines, fmt.Sprintf("📟 %s → %s", paneID, chatName))
+			return fmt.Sprintf("%d", chatID)
+		}
+		var lines []string
+		for key, chatID := range creds.RouteMap {
+			lines = append(lines, fmt.Sprintf("%s → %s", route.Label(key), chatTitle(chatID)))
 		}
 		for cwd, chatID := range creds.ProjectRouteMap {
-			chatName := fmt.Sprintf("%d", chatID)
-			if chat, err := bot.ChatByID(chatID); err == nil && chat.Title != "" {
-				chatName = chat.Title
+			lines = append(lines, fmt.Sprintf("📂 %s → %s", notify.CompressPath(cwd), chatTitle(chatID)))
+		}
+		if len(creds.RouteRules) > 0 {
+			lines = append(lines, "", tr(c, "routes.rules"))
+			for i, r := range creds.RouteRules {
+				lines = append(lines, fmt.Sprintf("%d. %s → %s", i+1, route.Describe(r), chatTitle(r.Chat)))
+			}
+			// Show which rule each active session matched
+			var matched []string
+			for _, info := range sessionState.all() {
+				if _, kind, ok := routedChat(&creds, info.tmuxTarget, info.cwd, info.route); ok && strings.HasPrefix(kind, "rule") {
+					matched = append(matched, fmt.Sprintf("📟 %s (%s) → %s", notify.FormatPaneID(info.tmuxTarget), notify.CompressPath(info.cwd), tr(c, "routes.matched", strings.TrimPrefix(kind, "rule "))))
+				}
+			}
+			if len(matched) > 0 {
+				sort.Strings(matched)
+				lines = append(lines, "", tr(c, "routes.sessions"))
+				lines = append(lines, matched...)
+			}
+		}
+		if len(creds.FanOut) > 0 {
+			lines = append(lines, "", tr(c, "routes.fanout"))
+			for i, f := range creds.FanOut {
+				events := tr(c, "routes.all_events")
+				if len(f.Events) > 0 {
+					events = strings.Join(f.Events, ", ")
+				}
+				lines = append(lines, fmt.Sprintf("%d. %s → %s [%s]", i+1, route.DescribeFanOut(f), chatTitle(f.Chat), events))
 			}
-			lines = append(lines, fmt.Sprintf("📂 %s → %s", notify.CompressPath(cwd), chatName))
 		}
-		return c.Send("🗺 Route bindings:\n" + strings.Join(lines, "\n"))
+		return c.Send(tr(c, "routes.title") + "\n" + strings.Join(lines, "\n"))
 	})
 
 	bot.Handle("/bot_bind", func(c tele.Context) error {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		if !pairing.IsAllowed(userID) {
-			return c.Reply("❌ Not paired. Use /bot_pair first.")
+			return c.Reply(tr(c, "err.not_paired"))
 		}
 		if c.Message().ReplyTo == nil {
-			return c.Reply("❌ Reply to a notification message with /bot_bind to bind that session to this chat.")
+			return c.Reply(tr(c, "bind.need_reply"))
 		}
-		target, err := extractTmuxTarget(c.Message().ReplyTo.Text)
+		target, err := messageTarget(c.Message().ReplyTo)
 		if err != nil {
-			return c.Reply("❌ No tmux session info (📟) found in the replied message.")
+			return c.Reply(tr(c, "err.no_pane_in_reply"))
 		}
 		tmuxStr := injector.FormatTarget(*target)
 		if tmuxStr == "" {
-			return c.Reply("❌ Empty tmux target, cannot bind.")
+			return c.Reply(tr(c, "bind.empty_target"))
 		}
 		creds, err := config.LoadCredentials()
 		if err != nil {
-			return c.Reply(fmt.Sprintf("❌ Failed to load config: %v", err))
+			return c.Reply(tr(c, "err.load_config", err))
 		}
+		// Bind to where the pane is now, not a cached location
+		refreshPaneLocation(tmuxStr)
+		id := sessionIdentity(tmuxStr)
+		paneKey, sessionKey := id.PaneRouteKey(), id.SessionRouteKey()
 		info := sessionState.findInfoByTarget(target.PaneID)
-		if info != nil && info.cwd != "" {
+		if info != nil && (info.cwd != "" || sessionKey != "") {
 			// Show choice buttons
 			sel := &tele.ReplyMarkup{}
-			btnTmux := sel.Data("📟 Tmux", "bind", "tmux")
-			btnProject := sel.Data("📂 Project", "bind", "project")
-			sel.Inline(sel.Row(btnTmux, btnProject))
-			sent, err := bot.Send(c.Chat(), fmt.Sprintf("Choose binding type:\n📟 %s\n📂 %s", tmuxStr, notify.CompressPath(info.cwd)), sel)
+			row := tele.Row{sel.Data(tr(c, "bind.btn_tmux"), "bind", "tmux")}
+			if sessionKey != "" {
+				row = append(row, sel.Data(tr(c, "bind.btn_session"), "bind", "session"))
+			}
+			if info.cwd != "" {
+				row = append(row, sel.Data(tr(c, "bind.btn_project"), "bind", "project"))
+			}
+			sel.Inline(row)
+			sent, err := outbox.send(c.Chat(), tr(c, "bind.choose", route.Label(paneKey), notify.CompressPath(info.cwd)), sel)
 			if err != nil {
-				return c.Reply(fmt.Sprintf("❌ Failed to send: %v", err))
+				return c.Reply(tr(c, "err.send_failed", err))
 			}
-			bindPending.Store(sent.ID, bindPendingInfo{tmuxTarget: tmuxStr, cwd: info.cwd, chatID: c.Chat().ID})
+			bindPending.Store(sent.ID, bindPendingInfo{tmuxTarget: tmuxStr, paneKey: paneKey, sessionKey: sessionKey, cwd: info.cwd, chatID: c.Chat().ID})
 			return nil
 		}
 		// No CWD available — bind tmux directly
-		creds.RouteMap[tmuxStr] = c.Chat().ID
+		unbindRouteKeys(&creds, tmuxStr)
+		creds.RouteMap[paneKey] = c.Chat().ID
 		if err := config.SaveCredentials(creds); err != nil {
-			return c.Reply(fmt.Sprintf("❌ Failed to save binding: %v", err))
+			return c.Reply(tr(c, "bind.save_failed", err))
 		}
-		logger.Info(fmt.Sprintf("Route bound: tmux=%s → chat=%d by user=%s", tmuxStr, c.Chat().ID, userID))
-		return c.Reply(fmt.Sprintf("✅ Bound tmux session to this chat.\n📟 %s", tmuxStr))
+		logger.Info(fmt.Sprintf("Route bound: tmux=%s key=%s → chat=%d by user=%s", tmuxStr, paneKey, c.Chat().ID, userID))
+		return c.Reply(tr(c, "bind.tmux_done", route.Label(paneKey)))
 	})
 
 	bot.Handle("/bot_unbind", func(c tele.Context) error {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		if !pairing.IsAllowed(userID) {
-			return c.Reply("❌ Not paired.")
+			return c.Reply(tr(c, "err.not_paired"))
 		}
 		if c.Message().ReplyTo == nil {
-			return c.Reply("❌ Reply to a notification message with /bot_unbind to unbind that session.")
+			return c.Reply(tr(c, "unbind.need_reply"))
 		}
-		target, err := extractTmuxTarget(c.Message().ReplyTo.Text)
+		target, err := messageTarget(c.Message().ReplyTo)
 		if err != nil {
-			return c.Reply("❌ No tmux session info (📟) found in the replied message.")
+			return c.Reply(tr(c, "err.no_pane_in_reply"))
 		}
 		tmuxStr := injector.FormatTarget(*target)
 		creds, err := config.LoadCredentials()
 		if err != nil {
-			return c.Reply(fmt.Sprintf("❌ Failed to load config: %v", err))
+			return c.Reply(tr(c, "err.load_config", err))
 		}
-		// Check tmux route first — direct unbind
-		if _, ok := creds.RouteMap[tmuxStr]; ok {
-			delete(creds.RouteMap, tmuxStr)
+		// Check tmux and session routes first — direct unbind
+		if removed := unbindRouteKeys(&creds, tmuxStr); len(removed) > 0 {
 			if err := config.SaveCredentials(creds); err != nil {
-				return c.Reply(fmt.Sprintf("❌ Failed to save: %v", err))
+				return c.Reply(tr(c, "err.save", err))
 			}
-			logger.Info(fmt.Sprintf("Route unbound (tmux): tmux=%s by user=%s", tmuxStr, userID))
-			return c.Reply(fmt.Sprintf("✅ Unbound tmux session.\n📟 %s", tmuxStr))
+			labels := make([]string, len(removed))
+			for i, key := range removed {
+				labels[i] = route.Label(key)
+			}
+			logger.Info(fmt.Sprintf("Route unbound (tmux): tmux=%s keys=%s by user=%s", tmuxStr, strings.Join(removed, ","), userID))
+			return c.Reply(tr(c, "unbind.tmux_done", strings.Join(labels, "\n")))
 		}
 		// Check project route — needs confirmation
 		if info := sessionState.findInfoByTarget(target.PaneID); info != nil && info.cwd != "" {
 			if _, ok := creds.ProjectRouteMap[info.cwd]; ok {
 				sel := &tele.ReplyMarkup{}
-				btnYes := sel.Data("✅ Yes, unbind", "unbind_confirm", "yes")
-				btnNo := sel.Data("❌ Cancel", "unbind_confirm", "no")
+				btnYes := sel.Data(tr(c, "unbind.btn_yes"), "unbind_confirm", "yes")
+				btnNo := sel.Data(tr(c, "unbind.btn_no"), "unbind_confirm", "no")
 				sel.Inline(sel.Row(btnYes, btnNo))
-				sent, err := bot.Send(c.Chat(), fmt.Sprintf("Unbind project route?\n📂 %s\n⚠️ This affects all sessions in this project.", notify.CompressPath(info.cwd)), sel)
+				sent, err := outbox.send(c.Chat(), tr(c, "unbind.confirm", notify.CompressPath(info.cwd)), sel)
 				if err != nil {
-					return c.Reply(fmt.Sprintf("❌ Failed to send: %v", err))
+					return c.Reply(tr(c, "err.send_failed", err))
 				}
 				unbindPending.Store(sent.ID, unbindPendingInfo{cwd: info.cwd})
 				return nil
 			}
 		}
-		return c.Reply("❌ No binding found for this session.")
+		return c.Reply(tr(c, "unbind.none"))
 	})
+
+	bot.Handle("/bot_alias", handleAliasCommand)
+	bot.Handle("/bot_use", handleUseCommand)
 	registerMessageHandlers(bot)
 	registerCallbackHandlers(bot)
+	registerReportCallbacks(bot)
+	registerRulesCallbacks(bot)
+	registerPendingCallbacks(bot)
+	registerUseCallbacks(bot)
+	registerMembershipHandlers(bot)
+	registerPlanCallbacks(bot)
 }
@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/route"
 	tele "gopkg.in/telebot.v3"
 )
 
@@ -20,29 +23,34 @@ func registerCallbackHandlers(bot *tele.Bot) {
 		if err != nil {
 			return c.Respond()
 		}
-		entry, ok := pages.get(c.Message().ID)
+		entry, ok := pages.get(keyOf(c.Message()))
 		if !ok {
-			return c.Respond(&tele.CallbackResponse{Text: "Page expired"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.page_expired")})
 		}
 		if pageNum < 1 || pageNum > len(entry.chunks) {
 			return c.Respond()
 		}
 		var text string
-		if entry.permRows != nil {
+		if entry.raw {
+			text = entry.chunks[pageNum-1]
+		} else if entry.permRows != nil {
 			text = entry.chunks[pageNum-1] + fmt.Sprintf("\n\n📄 %d/%d", pageNum, len(entry.chunks))
 		} else {
 			text = notify.BuildNotificationText(notify.NotificationData{
 				Event:      entry.event,
+				Subject:    entry.subject,
+				Failed:     entry.failed,
 				Project:    entry.project,
 				CWD:        entry.cwd,
 				Body:       entry.chunks[pageNum-1],
 				TmuxTarget: entry.tmuxTarget,
 				Page:       pageNum,
 				TotalPages: len(entry.chunks),
+				Lang:       chatLang(entry.chatID),
 			})
 		}
 		kb := buildPageKeyboardWithExtra(pageNum, len(entry.chunks), entry.permRows)
-		_, err = bot.Edit(c.Message(), text, kb)
+		_, err = outbox.edit(c.Message(), text, kb)
 		if err != nil {
 			logger.Debug(fmt.Sprintf("edit page error: %v", err))
 		}
@@ -51,18 +59,21 @@ func registerCallbackHandlers(bot *tele.Bot) {
 
 	bot.Handle(&tele.InlineButton{Unique: "perm"}, func(c tele.Context) error {
 		decision := c.Data()
+		if decision == "edit" {
+			return handlePermEditButton(c)
+		}
 		// Check session alive before resolving permission
-		if permTarget, ok := pendingPerms.getTarget(c.Message().ID); ok && permTarget != "" && !checkSessionAlive(permTarget, bot) {
-			return c.Respond(&tele.CallbackResponse{Text: "⚠️ Session disconnected"})
+		if permTarget, ok := pendingPerms.getTarget(c.Chat().ID, c.Message().ID); ok && permTarget != "" && !checkSessionAlive(permTarget, bot) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
 		}
-		uuid, uuidOk := pendingPerms.getUUID(c.Message().ID)
+		uuid, uuidOk := pendingPerms.getUUID(c.Chat().ID, c.Message().ID)
 		if !uuidOk {
-			uuid, uuidOk = pendingFiles.get(c.Message().ID)
+			uuid, uuidOk = pendingFiles.get(c.Chat().ID, c.Message().ID)
 		}
-		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(c.Message().ID))
-		d, err := resolvePermission(c.Message().ID, decision, nil)
+		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(c.Chat().ID, c.Message().ID), chatLang(c.Chat().ID))
+		d, err := resolvePermission(c.Chat().ID, c.Message().ID, decision, nil)
 		if err != nil {
-			return c.Respond(&tele.CallbackResponse{Text: "Expired or invalid"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired_or_invalid")})
 		}
 		if uuidOk {
 			var updatedPerms []interface{}
@@ -71,107 +82,133 @@ func registerCallbackHandlers(bot *tele.Bot) {
 				json.Unmarshal(d.UpdatedPermissions, &perms)
 				updatedPerms = perms
 			}
-			ccOutput := buildPermCCOutput(d.Behavior, d.Message, updatedPerms)
-			if err := writePendingAnswer(uuid, ccOutput); err != nil {
+			ccOutput := buildPermCCOutput(d.Behavior, d.Message, updatedPerms, nil)
+			if err := writePendingAnswer(uuid, keyOf(c.Message()), ccOutput); errors.Is(err, errAlreadyAnswered) {
+				showAnsweredElsewhere(c.Chat().ID, c.Message().ID, c.Message().Text)
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "perm.elsewhere")})
+			} else if err != nil {
 				logger.Error(fmt.Sprintf("Failed to write pending answer for perm: %v", err))
 			}
 		}
 		logger.Info(fmt.Sprintf("Permission resolved via TG button: msg_id=%d decision=%s uuid=%s", c.Message().ID, decision, uuid))
-		bot.Edit(c.Message(), c.Message().Text, buildFrozenPermMarkup(decision, sugLabels))
-		displayText := decision
-		if strings.HasPrefix(decision, "s") {
-			displayText = "Always Allow"
+		outbox.edit(c.Message(), c.Message().Text, buildFrozenPermMarkup(decision, sugLabels, chatLang(c.Chat().ID)))
+		lang := ctxLang(c)
+		displayText := i18n.T(lang, "perm.always_allow")
+		switch decision {
+		case "allow":
+			displayText = i18n.T(lang, "perm.allow")
+		case "deny":
+			displayText = i18n.T(lang, "perm.deny")
 		}
-		targetPtr, err := extractTmuxTarget(c.Message().Text)
+		targetPtr, err := messageTarget(c.Message())
 		if err == nil && targetPtr != nil {
 			reactAndTrack(bot, c.Message().Chat, c.Message(), injector.FormatTarget(*targetPtr))
 		}
-		return c.Respond(&tele.CallbackResponse{Text: "✅ " + displayText})
+		return c.Respond(&tele.CallbackResponse{Text: i18n.T(lang, "cb.decided", displayText)})
 	})
 
 	bot.Handle(&tele.InlineButton{Unique: "tool"}, func(c tele.Context) error {
 		parts := strings.SplitN(c.Data(), "|", 2)
 		if len(parts) < 2 {
-			return c.Respond(&tele.CallbackResponse{Text: "Invalid data"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
 		}
 		toolName := parts[0]
 		switch toolName {
 		case "AskUserQuestion":
-			entry, ok := toolNotifs.get(c.Message().ID)
+			entry, ok := toolNotifs.get(c.Chat().ID, c.Message().ID)
 			if !ok {
-				return c.Respond(&tele.CallbackResponse{Text: "Expired"})
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
 			}
 			// Check session alive before processing tool response
 			if entry.tmuxTarget != "" && !checkSessionAlive(entry.tmuxTarget, bot) {
-				return c.Respond(&tele.CallbackResponse{Text: "⚠️ Session disconnected"})
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
 			}
 			if entry.resolved {
-				return c.Respond(&tele.CallbackResponse{Text: "Already answered"})
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.already_answered")})
 			}
 			if parts[1] == "chat" {
-				uuid, ok := pendingFiles.get(c.Message().ID)
+				uuid, ok := pendingFiles.get(c.Chat().ID, c.Message().ID)
 				if !ok {
-					return c.Respond(&tele.CallbackResponse{Text: "Pending file not found"})
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.pending_missing")})
 				}
-				if handleStalePending(c.Message().ID, uuid, bot) {
-					return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+				if handleStalePending(c.Chat().ID, c.Message().ID, uuid, bot) {
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 				}
 				path := filepath.Join(pendingDir(), uuid+".json")
 				pf, err := readPendingFile(path)
 				if err != nil {
-					cleanupPendingState(c.Message().ID, uuid, bot, "file missing on chat button")
-					return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+					cleanupPendingState(c.Chat().ID, c.Message().ID, uuid, bot, "file missing on chat button")
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 				}
 				answers := map[string]string{"__chat": "true"}
 				ccOutput := buildAskCCOutput(pf.Payload, answers)
-				if err := writePendingAnswer(uuid, ccOutput); err != nil {
+				if err := writePendingAnswer(uuid, keyOf(c.Message()), ccOutput); errors.Is(err, errAlreadyAnswered) {
+					freezePendingMessage(c.Chat().ID, c.Message().ID)
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "perm.elsewhere")})
+				} else if err != nil {
 					logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
-					return c.Respond(&tele.CallbackResponse{Text: "Failed to save answer"})
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.save_failed")})
 				}
-				toolNotifs.markResolved(c.Message().ID)
-				bot.Edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, "💬 Chat mode selected"))
+				toolNotifs.markResolved(c.Chat().ID, c.Message().ID)
+				outbox.edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, tr(c, "question.chat_mode")))
 				logger.Info(fmt.Sprintf("AskUserQuestion 'Chat about this' selected: msg_id=%d uuid=%s", c.Message().ID, uuid))
-				return c.Respond(&tele.CallbackResponse{Text: "Chat mode"})
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.chat_mode")})
 			} else if parts[1] == "submit" {
-				uuid, ok := pendingFiles.get(c.Message().ID)
+				uuid, ok := pendingFiles.get(c.Chat().ID, c.Message().ID)
 				if !ok {
-					return c.Respond(&tele.CallbackResponse{Text: "Pending file not found"})
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.pending_missing")})
 				}
-				if handleStalePending(c.Message().ID, uuid, bot) {
-					return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+				if handleStalePending(c.Chat().ID, c.Message().ID, uuid, bot) {
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 				}
 				path := filepath.Join(pendingDir(), uuid+".json")
 				pf, err := readPendingFile(path)
 				if err != nil {
-					cleanupPendingState(c.Message().ID, uuid, bot, "file missing on submit button")
-					return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+					cleanupPendingState(c.Chat().ID, c.Message().ID, uuid, bot, "file missing on submit button")
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 				}
 				answers := buildAnswers(entry)
 				ccOutput := buildAskCCOutput(pf.Payload, answers)
-				if err := writePendingAnswer(uuid, ccOutput); err != nil {
+				if err := writePendingAnswer(uuid, keyOf(c.Message()), ccOutput); errors.Is(err, errAlreadyAnswered) {
+					freezePendingMessage(c.Chat().ID, c.Message().ID)
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "perm.elsewhere")})
+				} else if err != nil {
 					logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
-					return c.Respond(&tele.CallbackResponse{Text: "Failed to save answer"})
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.save_failed")})
 				}
-				toolNotifs.markResolved(c.Message().ID)
-				bot.Edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, ""))
+				toolNotifs.markResolved(c.Chat().ID, c.Message().ID)
+				outbox.edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, ""))
 				logger.Info(fmt.Sprintf("AskUserQuestion submitted: msg_id=%d uuid=%s answers=%v", c.Message().ID, uuid, answers))
-				return c.Respond(&tele.CallbackResponse{Text: "✅ Submitted"})
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.submitted")})
+			} else if qStr, ok := strings.CutPrefix(parts[1], "other:"); ok {
+				qIdx, err := strconv.Atoi(qStr)
+				if err != nil || qIdx < 0 || qIdx >= len(entry.questions) {
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_question")})
+				}
+				questionInputs.start(c.Chat().ID, c.Sender().ID, c.Message().ID, qIdx)
+				prompt := tr(c, "question.other_prompt", qIdx+1, entry.questions[qIdx].questionText, int(questionInputTTL.Minutes()))
+				if sent, err := outbox.send(c.Chat(), prompt, &tele.SendOptions{ReplyTo: c.Message()}); err == nil {
+					questionInputs.setPrompt(c.Chat().ID, c.Sender().ID, c.Message().ID, sent.ID)
+				}
+				logger.Info(fmt.Sprintf("AskUserQuestion awaiting Other answer: msg_id=%d q=%d user=%d", c.Message().ID, qIdx, c.Sender().ID))
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.other_waiting")})
 			} else {
 				split := strings.SplitN(parts[1], ":", 2)
 				qIdx, _ := strconv.Atoi(split[0])
 				optIdx, _ := strconv.Atoi(split[1])
 				if qIdx >= len(entry.questions) {
-					return c.Respond(&tele.CallbackResponse{Text: "Invalid question"})
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_question")})
 				}
 				qm := &entry.questions[qIdx]
 				if qm.multiSelect {
 					qm.selectedOptions[optIdx] = !qm.selectedOptions[optIdx]
 					logger.Info(fmt.Sprintf("AskUserQuestion multiSelect toggle: msg_id=%d q=%d opt=%d state=%v label=%s", c.Message().ID, qIdx, optIdx, qm.selectedOptions[optIdx], qm.optionLabels[optIdx]))
 					newMarkup := rebuildAskMarkup(entry)
-					bot.Edit(c.Message(), c.Message().Text, newMarkup)
-					return c.Respond(&tele.CallbackResponse{Text: "Toggled"})
+					outbox.edit(c.Message(), c.Message().Text, newMarkup)
+					return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.toggled")})
 				} else {
 					qm.selectedOption = optIdx
+					qm.customAnswer = ""
 					hasSubmit := len(entry.questions) > 1
 					for _, q := range entry.questions {
 						if q.multiSelect {
@@ -179,39 +216,42 @@ func registerCallbackHandlers(bot *tele.Bot) {
 						}
 					}
 					if !hasSubmit {
-						uuid, ok := pendingFiles.get(c.Message().ID)
+						uuid, ok := pendingFiles.get(c.Chat().ID, c.Message().ID)
 						if !ok {
-							return c.Respond(&tele.CallbackResponse{Text: "Pending file not found"})
+							return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.pending_missing")})
 						}
-						if handleStalePending(c.Message().ID, uuid, bot) {
-							return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+						if handleStalePending(c.Chat().ID, c.Message().ID, uuid, bot) {
+							return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 						}
 						path := filepath.Join(pendingDir(), uuid+".json")
 						pf, err := readPendingFile(path)
 						if err != nil {
-							cleanupPendingState(c.Message().ID, uuid, bot, "file missing on option select")
-							return c.Respond(&tele.CallbackResponse{Text: "❌ Question expired"})
+							cleanupPendingState(c.Chat().ID, c.Message().ID, uuid, bot, "file missing on option select")
+							return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.question_expired")})
 						}
 						answers := buildAnswers(entry)
 						ccOutput := buildAskCCOutput(pf.Payload, answers)
-						if err := writePendingAnswer(uuid, ccOutput); err != nil {
+						if err := writePendingAnswer(uuid, keyOf(c.Message()), ccOutput); errors.Is(err, errAlreadyAnswered) {
+							freezePendingMessage(c.Chat().ID, c.Message().ID)
+							return c.Respond(&tele.CallbackResponse{Text: tr(c, "perm.elsewhere")})
+						} else if err != nil {
 							logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
-							return c.Respond(&tele.CallbackResponse{Text: "Failed to save answer"})
+							return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.save_failed")})
 						}
-						toolNotifs.markResolved(c.Message().ID)
-						bot.Edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, ""))
+						toolNotifs.markResolved(c.Chat().ID, c.Message().ID)
+						outbox.edit(c.Message(), c.Message().Text, buildFrozenMarkup(entry, ""))
 						logger.Info(fmt.Sprintf("AskUserQuestion auto-resolved: msg_id=%d uuid=%s answers=%v", c.Message().ID, uuid, answers))
-						return c.Respond(&tele.CallbackResponse{Text: "✅ Selected"})
+						return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.selected_done")})
 					} else {
 						logger.Info(fmt.Sprintf("AskUserQuestion option selected: msg_id=%d q=%d opt=%d label=%s", c.Message().ID, qIdx, optIdx, qm.optionLabels[optIdx]))
 						newMarkup := rebuildAskMarkup(entry)
-						bot.Edit(c.Message(), c.Message().Text, newMarkup)
-						return c.Respond(&tele.CallbackResponse{Text: "Selected"})
+						outbox.edit(c.Message(), c.Message().Text, newMarkup)
+						return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.selected")})
 					}
 				}
 			}
 		}
-		if entry, ok := toolNotifs.get(c.Message().ID); ok {
+		if entry, ok := toolNotifs.get(c.Chat().ID, c.Message().ID); ok {
 			reactAndTrack(bot, c.Message().Chat, c.Message(), entry.tmuxTarget)
 		}
 		return c.Respond()
@@ -220,7 +260,7 @@ func registerCallbackHandlers(bot *tele.Bot) {
 	bot.Handle(&tele.InlineButton{Unique: "bind"}, func(c tele.Context) error {
 		val, ok := bindPending.Load(c.Message().ID)
 		if !ok {
-			return c.Respond(&tele.CallbackResponse{Text: "Expired"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
 		}
 		bp := val.(bindPendingInfo)
 		bindType := c.Data() // "tmux" or "project"
@@ -228,39 +268,72 @@ func registerCallbackHandlers(bot *tele.Bot) {
 
 		creds, err := config.LoadCredentials()
 		if err != nil {
-			bot.Edit(c.Message(), fmt.Sprintf("❌ Failed to load config: %v", err))
+			outbox.edit(c.Message(), tr(c, "err.load_config", err))
 			return c.Respond()
 		}
 		var resultMsg string
-		if bindType == "tmux" {
-			creds.RouteMap[bp.tmuxTarget] = bp.chatID
-			resultMsg = fmt.Sprintf("✅ Bound tmux session to this chat.\n📟 %s", bp.tmuxTarget)
-			logger.Info(fmt.Sprintf("Route bound (tmux): tmux=%s → chat=%d", bp.tmuxTarget, bp.chatID))
-		} else {
+		switch bindType {
+		case "tmux", "session":
+			key := bp.paneKey
+			if bindType == "session" {
+				key = bp.sessionKey
+			}
+			// One key per session, so later lookups can't hit a stale one
+			unbindRouteKeys(&creds, bp.tmuxTarget)
+			creds.RouteMap[key] = bp.chatID
+			resultMsg = tr(c, "bind."+bindType+"_done", route.Label(key))
+			logger.Info(fmt.Sprintf("Route bound (%s): tmux=%s key=%s → chat=%d", bindType, bp.tmuxTarget, key, bp.chatID))
+		default:
 			creds.ProjectRouteMap[bp.cwd] = bp.chatID
-			resultMsg = fmt.Sprintf("✅ Bound project to this chat.\n📂 %s", notify.CompressPath(bp.cwd))
+			resultMsg = tr(c, "bind.project_done", notify.CompressPath(bp.cwd))
 			logger.Info(fmt.Sprintf("Route bound (project): cwd=%s → chat=%d", bp.cwd, bp.chatID))
 		}
 		if err := config.SaveCredentials(creds); err != nil {
-			bot.Edit(c.Message(), fmt.Sprintf("❌ Failed to save: %v", err))
+			outbox.edit(c.Message(), tr(c, "err.save", err))
 			return c.Respond()
 		}
-		bot.Edit(c.Message(), resultMsg)
+		outbox.edit(c.Message(), resultMsg)
 		return c.Respond()
 	})
 
+	bot.Handle(&tele.InlineButton{Unique: "ctx"}, func(c tele.Context) error {
+		var command string
+		switch c.Data() {
+		case "compact":
+			command = "/compact"
+		case "clear":
+			command = "/clear"
+		default:
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+		}
+		targetPtr, err := messageTarget(c.Message())
+		if err != nil || targetPtr == nil {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.no_target")})
+		}
+		if !checkSessionAlive(injector.FormatTarget(*targetPtr), bot) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
+		}
+		if err := injector.InjectText(*targetPtr, command); err != nil {
+			logger.Error(fmt.Sprintf("context command inject failed: target=%s cmd=%s err=%v", injector.FormatTarget(*targetPtr), command, err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.inject_failed")})
+		}
+		logger.Info(fmt.Sprintf("Context command injected: target=%s cmd=%s", injector.FormatTarget(*targetPtr), command))
+		reactAndTrack(bot, c.Message().Chat, c.Message(), injector.FormatTarget(*targetPtr))
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "context.sent", command)})
+	})
+
 	bot.Handle(&tele.InlineButton{Unique: "resume"}, func(c tele.Context) error {
 		sessionID := c.Data()
-		targetPtr, err := extractTmuxTarget(c.Message().Text)
+		targetPtr, err := messageTarget(c.Message())
 		if err != nil || targetPtr == nil {
-			return c.Respond(&tele.CallbackResponse{Text: "No tmux target found"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.no_target")})
 		}
 		if !checkSessionAlive(injector.FormatTarget(*targetPtr), bot) {
-			return c.Respond(&tele.CallbackResponse{Text: "⚠️ Session disconnected"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
 		}
 		if err := injector.InjectText(*targetPtr, "/resume "+sessionID); err != nil {
 			logger.Error(fmt.Sprintf("resume inject failed: target=%s session=%s err=%v", injector.FormatTarget(*targetPtr), sessionID, err))
-			return c.Respond(&tele.CallbackResponse{Text: "❌ Injection failed"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.inject_failed")})
 		}
 		logger.Info(fmt.Sprintf("Resume injected: target=%s session=%s", injector.FormatTarget(*targetPtr), sessionID))
 		// Rebuild keyboard with ✅ on selected button
@@ -283,38 +356,38 @@ func registerCallbackHandlers(bot *tele.Bot) {
 			rows = append(rows, markup.Row(btns...))
 		}
 		markup.Inline(rows...)
-		if _, err := bot.Edit(c.Message(), c.Message().Text, markup); err != nil {
+		if _, err := outbox.edit(c.Message(), c.Message().Text, markup); err != nil {
 			logger.Debug(fmt.Sprintf("resume edit markup error: %v", err))
 		}
 		reactAndTrack(bot, c.Message().Chat, c.Message(), injector.FormatTarget(*targetPtr))
-		return c.Respond(&tele.CallbackResponse{Text: "✅ Resuming"})
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.resuming")})
 	})
 
 	bot.Handle(&tele.InlineButton{Unique: "unbind_confirm"}, func(c tele.Context) error {
 		action := c.Data() // "yes" or "no"
 		val, ok := unbindPending.Load(c.Message().ID)
 		if !ok {
-			return c.Respond(&tele.CallbackResponse{Text: "Expired"})
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
 		}
 		up := val.(unbindPendingInfo)
 		unbindPending.Delete(c.Message().ID)
 
 		if action != "yes" {
-			bot.Edit(c.Message(), "❌ Unbind cancelled.")
+			outbox.edit(c.Message(), tr(c, "unbind.cancelled"))
 			return c.Respond()
 		}
 		creds, err := config.LoadCredentials()
 		if err != nil {
-			bot.Edit(c.Message(), fmt.Sprintf("❌ Failed to load config: %v", err))
+			outbox.edit(c.Message(), tr(c, "err.load_config", err))
 			return c.Respond()
 		}
 		delete(creds.ProjectRouteMap, up.cwd)
 		if err := config.SaveCredentials(creds); err != nil {
-			bot.Edit(c.Message(), fmt.Sprintf("❌ Failed to save: %v", err))
+			outbox.edit(c.Message(), tr(c, "err.save", err))
 			return c.Respond()
 		}
 		logger.Info(fmt.Sprintf("Route unbound (project): cwd=%s", up.cwd))
-		bot.Edit(c.Message(), fmt.Sprintf("✅ Unbound project route.\n📂 %s", notify.CompressPath(up.cwd)))
+		outbox.edit(c.Message(), tr(c, "unbind.project_done", notify.CompressPath(up.cwd)))
 		return c.Respond()
 	})
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
@@ -17,15 +19,55 @@ import (
 	tele "gopkg.in/telebot.v3"
 )
 
-// resolveGroupTarget finds the unique bound tmux target for a group chat.
-// Checks both direct tmux routes and project routes with active sessions.
-func resolveGroupTarget(chatID int64) (string, injector.TmuxTarget, error) {
+// resolveGroupTarget finds the unique bound tmux target for a message in a
+// group chat. A message in a session's forum topic goes to that session;
+// otherwise the sessions bound to the chat are checked, and when there are
+// several the one picked with /bot_use is taken.
+func resolveGroupTarget(msg *tele.Message) (string, injector.TmuxTarget, error) {
+	chatID := msg.Chat.ID
+	if thread := topicThread(msg); thread != 0 {
+		if t, ok := sessionTopics.byThread(chatID, thread); ok {
+			target, err := injector.ParseTarget(t.TmuxTarget)
+			if err != nil || !injector.SessionExists(target) {
+				return "", injector.TmuxTarget{}, fmt.Errorf("session not found")
+			}
+			return t.TmuxTarget, target, nil
+		}
+	}
+	targets := groupTargets(chatID)
+	if len(targets) == 0 {
+		return "", injector.TmuxTarget{}, fmt.Errorf("no targets bound")
+	}
+	if len(targets) > 1 {
+		var userID int64
+		if msg.Sender != nil {
+			userID = msg.Sender.ID
+		}
+		t, _, ok := activeGroupTarget(chatID, userID, targets)
+		if !ok {
+			return "", injector.TmuxTarget{}, fmt.Errorf("multiple sessions bound")
+		}
+		targets = []string{t}
+	}
+	target, err := injector.ParseTarget(targets[0])
+	if err != nil || !injector.SessionExists(target) {
+		return "", injector.TmuxTarget{}, fmt.Errorf("session not found")
+	}
+	return targets[0], target, nil
+}
+
+// groupTargets returns the tmux targets of the sessions bound to a group
+// chat: direct tmux routes, project routes with active sessions, and active
+// sessions a routing rule sends there.
+func groupTargets(chatID int64) []string {
 	creds, _ := config.LoadCredentials()
 	var targets []string
-	// Direct tmux routes
-	for t, cid := range creds.RouteMap {
+	// Direct tmux routes, by the pane each key currently refers to
+	for key, cid := range creds.RouteMap {
 		if cid == chatID {
-			targets = append(targets, t)
+			if t := routeKeyTarget(key); t != "" {
+				targets = append(targets, t)
+			}
 		}
 	}
 	// Project routes: find active sessions with matching CWD
@@ -66,17 +108,27 @@ func resolveGroupTarget(chatID int64) (string, injector.TmuxTarget, error) {
 			}
 		}
 	}
-	if len(targets) == 0 {
-		return "", injector.TmuxTarget{}, fmt.Errorf("no targets bound")
-	}
-	if len(targets) > 1 {
-		return "", injector.TmuxTarget{}, fmt.Errorf("multiple sessions bound")
-	}
-	target, err := injector.ParseTarget(targets[0])
-	if err != nil || !injector.SessionExists(target) {
-		return "", injector.TmuxTarget{}, fmt.Errorf("session not found")
+	// Active sessions a routing rule sends here
+	if len(creds.RouteRules) > 0 {
+		for _, info := range sessionState.all() {
+			cid, kind, ok := routedChat(&creds, info.tmuxTarget, info.cwd, info.route)
+			if !ok || cid != chatID || !strings.HasPrefix(kind, "rule") {
+				continue
+			}
+			normalized := notify.FormatPaneID(info.tmuxTarget)
+			found := false
+			for _, t := range targets {
+				if notify.FormatPaneID(t) == normalized {
+					found = true
+					break
+				}
+			}
+			if !found {
+				targets = append(targets, info.tmuxTarget)
+			}
+		}
 	}
-	return targets[0], target, nil
+	return targets
 }
 
 // transcribeVoice downloads and transcribes a voice message
@@ -99,16 +151,18 @@ func transcribeVoice(bot *tele.Bot, fileID string) (string, error) {
 
 // reactAndTrack adds a reaction emoji and records it in the tracker
 func reactAndTrack(bot *tele.Bot, chat *tele.Chat, msg *tele.Message, tmuxTarget string) {
-	if err := bot.React(chat, msg, tele.ReactionOptions{
-		Reactions: []tele.Reaction{{Type: "emoji", Emoji: "✍"}},
+	if err := outbox.do(chat.ID, func() error {
+		return bot.React(chat, msg, tele.ReactionOptions{
+			Reactions: []tele.Reaction{{Type: "emoji", Emoji: "✍"}},
+		})
 	}); err == nil {
 		reactionTracker.record(tmuxTarget, chat.ID, msg.ID)
 	}
 }
 
 // resolveReplyTarget extracts and validates tmux target from reply message
-func resolveReplyTarget(replyText string) (injector.TmuxTarget, error) {
-	targetPtr, err := extractTmuxTarget(replyText)
+func resolveReplyTarget(reply *tele.Message) (injector.TmuxTarget, error) {
+	targetPtr, err := messageTarget(reply)
 	if err != nil {
 		return injector.TmuxTarget{}, fmt.Errorf("no target found")
 	}
@@ -123,9 +177,18 @@ func resolveReplyTarget(replyText string) (injector.TmuxTarget, error) {
 // text is the raw transcribed or typed text; isVoice indicates input method.
 // voicePrefix is prepended to injected text when isVoice is true.
 func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool, voicePrefix string) error {
-	answerLabel := "✅ Text answer"
+	replyToID := 0
+	if c.Message().ReplyTo != nil {
+		replyToID = c.Message().ReplyTo.ID
+	}
+	if in, ok := questionInputs.take(c.Chat().ID, c.Sender().ID, replyToID); ok {
+		if entry, ok := toolNotifs.get(c.Chat().ID, in.msgID); ok && !entry.resolved && in.qIdx < len(entry.questions) {
+			return applyOtherAnswer(c, in, entry, text)
+		}
+	}
+	answerLabel := i18n.T(chatLang(c.Chat().ID), "question.text_answer")
 	if isVoice {
-		answerLabel = "✅ Voice answer"
+		answerLabel = i18n.T(chatLang(c.Chat().ID), "question.voice_answer")
 	}
 	injectionText := text
 	if isVoice {
@@ -134,12 +197,17 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 	// sendFeedback sends the appropriate feedback message for a group or reply context
 	sendFeedback := func(tmuxTarget string) {
 		if isVoice {
-			sentMsg, _ := bot.Reply(c.Message(), voicePrefix+" "+text)
+			echo := voicePrefix + " " + text
+			if note := activeNote(c.Message(), tmuxTarget); note != "" {
+				echo += "\n\n" + note
+			}
+			sentMsg, _ := outbox.send(c.Chat(), echo, &tele.SendOptions{ReplyTo: c.Message()})
 			if sentMsg != nil {
 				reactAndTrack(bot, c.Message().Chat, sentMsg, tmuxTarget)
 			}
 		} else {
 			reactAndTrack(bot, c.Message().Chat, c.Message(), tmuxTarget)
+			replyActiveNote(c, tmuxTarget)
 		}
 	}
 
@@ -152,37 +220,42 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 		if c.Message().OriginalUnixtime != 0 {
 			return nil
 		}
-		tmuxStr, target, err := resolveGroupTarget(c.Chat().ID)
+		tmuxStr, target, err := resolveGroupTarget(c.Message())
 		if err != nil {
 			if err.Error() == "no targets bound" {
 				return nil
 			}
 			if err.Error() == "multiple sessions bound" {
-				return c.Reply("❌ Multiple sessions bound to this group. Reply to a specific notification.")
+				return c.Reply(tr(c, "err.multiple_sessions"))
 			}
-			return c.Reply("❌ tmux session not found.")
+			return c.Reply(tr(c, "err.session_not_found"))
 		}
-		if msgID, entry, ok := toolNotifs.findByTmuxTarget(tmuxStr); ok {
-			uuid, uuidOk := pendingFiles.get(msgID)
+		if k, entry, ok := toolNotifs.findByTmuxTarget(c.Chat().ID, tmuxStr); ok {
+			msgID := k.msgID
+			uuid, uuidOk := pendingFiles.get(k.chatID, msgID)
 			if uuidOk {
-				if handleStalePending(msgID, uuid, bot) {
+				if handleStalePending(k.chatID, msgID, uuid, bot) {
 					// Stale: hook dead or file missing, fall through to InjectText
 				} else {
 					path := filepath.Join(pendingDir(), uuid+".json")
 					pf, err := readPendingFile(path)
 					if err == nil {
-						answers := make(map[string]string)
+						// Text answers the first question; choices already made on the others are kept
+						answers := buildAnswers(entry)
 						if len(entry.questions) > 0 {
 							answers[entry.questions[0].questionText] = text
 						}
 						ccOutput := buildAskCCOutput(pf.Payload, answers)
-						if err := writePendingAnswer(uuid, ccOutput); err != nil {
+						if err := writePendingAnswer(uuid, k, ccOutput); errors.Is(err, errAlreadyAnswered) {
+							freezePendingMessage(k.chatID, msgID)
+							return c.Reply(tr(c, "perm.elsewhere"))
+						} else if err != nil {
 							logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
 						} else {
-							toolNotifs.markResolved(msgID)
+							toolNotifs.markResolved(k.chatID, msgID)
 							logger.Info(fmt.Sprintf("AskUserQuestion custom text via group direct msg: msg_id=%d uuid=%s text=%s", msgID, uuid, truncateStr(text, 200)))
 							editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: entry.chatID}}
-							bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, answerLabel))
+							outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, answerLabel))
 						}
 						sendFeedback(tmuxStr)
 						return nil
@@ -191,10 +264,10 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 			}
 		}
 		if !checkSessionAlive(tmuxStr, bot) {
-			return c.Reply("⚠️ Session is no longer running. Tmux route has been unbound.")
+			return c.Reply(tr(c, "session.not_running"))
 		}
 		if err := injector.InjectText(target, injectionText); err != nil {
-			return c.Reply(fmt.Sprintf("❌ Injection failed: %v", err))
+			return c.Reply(tr(c, "err.inject_failed", err))
 		}
 		logger.Info(fmt.Sprintf("Group quick reply: target=%s voice=%v text=%s", tmuxStr, isVoice, truncateStr(text, 200)))
 		sendFeedback(tmuxStr)
@@ -203,12 +276,15 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 
 	// Reply path: ReplyTo != nil
 	replyTo := c.Message().ReplyTo
-	if _, ok := pendingPerms.getTarget(replyTo.ID); ok {
-		uuid, uuidOk := pendingPerms.getUUID(replyTo.ID)
+	if permMsgID, ok := permEditPrompts.get(c.Chat().ID, replyTo.ID); ok {
+		return applyPermEdit(c, bot, permMsgID, text)
+	}
+	if _, ok := pendingPerms.getTarget(c.Chat().ID, replyTo.ID); ok {
+		uuid, uuidOk := pendingPerms.getUUID(c.Chat().ID, replyTo.ID)
 		if !uuidOk {
-			uuid, uuidOk = pendingFiles.get(replyTo.ID)
+			uuid, uuidOk = pendingFiles.get(c.Chat().ID, replyTo.ID)
 		}
-		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(replyTo.ID))
+		sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(c.Chat().ID, replyTo.ID), chatLang(c.Chat().ID))
 		denyMsg := "User provided custom input: " + text
 		if isVoice {
 			denyMsg = "User provided voice input: " + text
@@ -217,16 +293,19 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 			Behavior: "deny",
 			Message:  denyMsg,
 		}
-		pendingPerms.resolve(replyTo.ID, d)
+		pendingPerms.resolve(c.Chat().ID, replyTo.ID, d)
 		if uuidOk {
-			ccOutput := buildPermCCOutput(d.Behavior, d.Message, nil)
-			if err := writePendingAnswer(uuid, ccOutput); err != nil {
+			ccOutput := buildPermCCOutput(d.Behavior, d.Message, nil, nil)
+			if err := writePendingAnswer(uuid, keyOf(replyTo), ccOutput); errors.Is(err, errAlreadyAnswered) {
+				showAnsweredElsewhere(c.Chat().ID, replyTo.ID, replyTo.Text)
+				return c.Reply(tr(c, "perm.elsewhere"))
+			} else if err != nil {
 				logger.Error(fmt.Sprintf("Failed to write pending answer for perm: %v", err))
 			}
 		}
 		editMsg := &tele.Message{ID: replyTo.ID, Chat: &tele.Chat{ID: c.Chat().ID}}
-		bot.Edit(editMsg, replyTo.Text, buildFrozenPermMarkup("deny", sugLabels))
-		targetPtr, err := extractTmuxTarget(replyTo.Text)
+		outbox.edit(editMsg, replyTo.Text, buildFrozenPermMarkup("deny", sugLabels, chatLang(c.Chat().ID)))
+		targetPtr, err := messageTarget(replyTo)
 		if err == nil && targetPtr != nil {
 			target := *targetPtr
 			if injector.SessionExists(target) {
@@ -238,26 +317,26 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 		return nil
 	}
 
-	if entry, ok := toolNotifs.get(replyTo.ID); ok {
+	if entry, ok := toolNotifs.get(c.Chat().ID, replyTo.ID); ok {
 		target, err := injector.ParseTarget(entry.tmuxTarget)
 		if err != nil || !injector.SessionExists(target) {
-			return c.Reply("❌ tmux session not found.")
+			return c.Reply(tr(c, "err.session_not_found"))
 		}
 		switch entry.toolName {
 		case "AskUserQuestion":
 			if entry.resolved {
-				toolNotifs.markResolved(replyTo.ID)
+				toolNotifs.markResolved(c.Chat().ID, replyTo.ID)
 				injector.InjectText(target, injectionText)
 				return nil
 			}
-			uuid, ok := pendingFiles.get(replyTo.ID)
+			uuid, ok := pendingFiles.get(c.Chat().ID, replyTo.ID)
 			if !ok {
 				// No pending file mapping, treat as stale
-				toolNotifs.markResolved(replyTo.ID)
+				toolNotifs.markResolved(c.Chat().ID, replyTo.ID)
 				injector.InjectText(target, injectionText)
 				return nil
 			}
-			if handleStalePending(replyTo.ID, uuid, bot) {
+			if handleStalePending(c.Chat().ID, replyTo.ID, uuid, bot) {
 				// Stale: hook dead or file missing, inject text
 				injector.InjectText(target, injectionText)
 				return nil
@@ -270,20 +349,23 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 					break
 				}
 				// For voice: unexpected read error after stale check
-				return c.Reply("❌ Failed to read pending file.")
+				return c.Reply(tr(c, "err.read_pending"))
 			}
-			answers := make(map[string]string)
+			answers := buildAnswers(entry)
 			if len(entry.questions) > 0 {
 				answers[entry.questions[0].questionText] = text
 			}
 			ccOutput := buildAskCCOutput(pf.Payload, answers)
-			if err := writePendingAnswer(uuid, ccOutput); err != nil {
+			if err := writePendingAnswer(uuid, keyOf(replyTo), ccOutput); errors.Is(err, errAlreadyAnswered) {
+				freezePendingMessage(c.Chat().ID, replyTo.ID)
+				return c.Reply(tr(c, "perm.elsewhere"))
+			} else if err != nil {
 				logger.Error(fmt.Sprintf("Failed to write pending answer: %v", err))
 			} else {
-				toolNotifs.markResolved(replyTo.ID)
+				toolNotifs.markResolved(c.Chat().ID, replyTo.ID)
 				logger.Info(fmt.Sprintf("AskUserQuestion custom reply: msg_id=%d uuid=%s voice=%v text=%s", replyTo.ID, uuid, isVoice, truncateStr(text, 200)))
 				editMsg := &tele.Message{ID: replyTo.ID, Chat: &tele.Chat{ID: entry.chatID}}
-				bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, answerLabel))
+				outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, answerLabel))
 				sendFeedback(entry.tmuxTarget)
 				return nil
 			}
@@ -294,27 +376,33 @@ func processUserInput(c tele.Context, bot *tele.Bot, text string, isVoice bool,
 	}
 
 	// General reply path
-	target, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+	target, err := resolveReplyTarget(c.Message().ReplyTo)
 	if err != nil {
-		return c.Reply("❌ No tmux session info found in the original message.")
+		return c.Reply(tr(c, "err.no_target_in_msg"))
+	}
+	if contextAlerts.isAlert(c.Chat().ID, replyTo.ID) {
+		// Replying to a context alert compacts with the reply as focus
+		injectionText = "/compact " + injectionText
 	}
 	if !checkSessionAlive(injector.FormatTarget(target), bot) {
-		return c.Reply("⚠️ Session is no longer running. Tmux route has been unbound.")
+		return c.Reply(tr(c, "session.not_running"))
 	}
 	if err := injector.InjectText(target, injectionText); err != nil {
 		logger.Error(fmt.Sprintf("Injection failed: %v", err))
-		return c.Reply(fmt.Sprintf("❌ Injection failed: %v", err))
+		return c.Reply(tr(c, "err.inject_failed", err))
 	}
 	logger.Info(fmt.Sprintf("Injected reply to %s voice=%v text=%s", injector.FormatTarget(target), isVoice, truncateStr(text, 200)))
 	if isVoice {
 		tmuxStr := injector.FormatTarget(target)
-		sentMsg, _ := bot.Reply(c.Message(), voicePrefix+" "+text)
+		sentMsg, _ := outbox.send(c.Chat(), voicePrefix+" "+text, &tele.SendOptions{ReplyTo: c.Message()})
 		if sentMsg != nil {
 			reactAndTrack(bot, c.Message().Chat, sentMsg, tmuxStr)
 		}
 	} else {
-		if err := bot.React(c.Message().Chat, c.Message(), tele.ReactionOptions{
-			Reactions: []tele.Reaction{{Type: "emoji", Emoji: "✍"}},
+		if err := outbox.do(c.Chat().ID, func() error {
+			return bot.React(c.Message().Chat, c.Message(), tele.ReactionOptions{
+				Reactions: []tele.Reaction{{Type: "emoji", Emoji: "✍"}},
+			})
 		}); err != nil {
 			logger.Debug(fmt.Sprintf("React failed: %v, falling back to reply", err))
 			return c.Reply("✅")
@@ -335,20 +423,21 @@ func registerMessageHandlers(bot *tele.Bot) {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		chatID := strconv.FormatInt(c.Chat().ID, 10)
 		if !pairing.IsAllowed(userID) && !pairing.IsAllowed(chatID) {
-			return c.Send("Not paired. Use /bot_pair first.")
+			return c.Send(tr(c, "status.unpaired"))
 		}
 		if c.Message().ReplyTo == nil {
 			if c.Chat().Type == "group" || c.Chat().Type == "supergroup" {
 				isCmd := strings.HasPrefix(c.Message().Text, "/bot_perm_") ||
 					c.Message().Text == "/bot_capture" || strings.HasPrefix(c.Message().Text, "/bot_capture@") ||
-					c.Message().Text == "/bot_escape" || strings.HasPrefix(c.Message().Text, "/bot_escape@")
+					c.Message().Text == "/bot_escape" || strings.HasPrefix(c.Message().Text, "/bot_escape@") ||
+					isBotCommand(c.Message().Text, "/bot_git") || isBotCommand(c.Message().Text, "/bot_rules")
 				if isCmd {
-					_, target, err := resolveGroupTarget(c.Chat().ID)
+					_, target, err := resolveGroupTarget(c.Message())
 					if err != nil {
 						if err.Error() == "multiple sessions bound" {
-							return c.Reply("❌ Multiple sessions bound to this group. Reply to a specific notification.")
+							return c.Reply(tr(c, "err.multiple_sessions"))
 						}
-						return c.Reply("❌ tmux session not found.")
+						return c.Reply(tr(c, "err.session_not_found"))
 					}
 					if strings.HasPrefix(c.Message().Text, "/bot_perm_") {
 						return handlePermCommand(c, target)
@@ -356,31 +445,51 @@ func registerMessageHandlers(bot *tele.Bot) {
 					if c.Message().Text == "/bot_capture" || strings.HasPrefix(c.Message().Text, "/bot_capture@") {
 						return handleCaptureCommand(c, target)
 					}
+					if isBotCommand(c.Message().Text, "/bot_git") {
+						return handleGitCommand(c, target)
+					}
+					if isBotCommand(c.Message().Text, "/bot_rules") {
+						return handleRulesCommand(c, target)
+					}
 					return handleEscapeCommand(c, target)
 				}
 			}
 		} else {
 			if strings.HasPrefix(c.Message().Text, "/bot_perm_") {
-				target, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+				target, err := resolveReplyTarget(c.Message().ReplyTo)
 				if err != nil {
-					return c.Reply("❌ No tmux session info found.")
+					return c.Reply(tr(c, "err.no_target"))
 				}
 				return handlePermCommand(c, target)
 			}
 			if c.Message().Text == "/bot_capture" || strings.HasPrefix(c.Message().Text, "/bot_capture@") {
-				target, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+				target, err := resolveReplyTarget(c.Message().ReplyTo)
 				if err != nil {
-					return c.Reply("❌ No tmux session info found.")
+					return c.Reply(tr(c, "err.no_target"))
 				}
 				return handleCaptureCommand(c, target)
 			}
 			if c.Message().Text == "/bot_escape" || strings.HasPrefix(c.Message().Text, "/bot_escape@") {
-				target, err := resolveReplyTarget(c.Message().ReplyTo.Text)
+				target, err := resolveReplyTarget(c.Message().ReplyTo)
 				if err != nil {
-					return c.Reply("❌ No tmux session info found.")
+					return c.Reply(tr(c, "err.no_target"))
 				}
 				return handleEscapeCommand(c, target)
 			}
+			if isBotCommand(c.Message().Text, "/bot_git") {
+				target, err := resolveReplyTarget(c.Message().ReplyTo)
+				if err != nil {
+					return c.Reply(tr(c, "err.no_target"))
+				}
+				return handleGitCommand(c, target)
+			}
+			if isBotCommand(c.Message().Text, "/bot_rules") {
+				target, err := resolveReplyTarget(c.Message().ReplyTo)
+				if err != nil {
+					return c.Reply(tr(c, "err.no_target"))
+				}
+				return handleRulesCommand(c, target)
+			}
 		}
 		return processUserInput(c, bot, c.Message().Text, false, voicePrefix)
 	})
@@ -389,7 +498,7 @@ func registerMessageHandlers(bot *tele.Bot) {
 		userID := strconv.FormatInt(c.Sender().ID, 10)
 		chatID := strconv.FormatInt(c.Chat().ID, 10)
 		if !pairing.IsAllowed(userID) && !pairing.IsAllowed(chatID) {
-			return c.Send("Not paired. Use /bot_pair first.")
+			return c.Send(tr(c, "status.unpaired"))
 		}
 		if c.Message().ReplyTo == nil {
 			if c.Chat().Type != "group" && c.Chat().Type != "supergroup" {
@@ -397,17 +506,31 @@ func registerMessageHandlers(bot *tele.Bot) {
 			}
 			text, err := transcribeVoice(bot, c.Message().Voice.FileID)
 			if err != nil || text == "" {
-				return c.Reply("❌ Transcription failed or empty.")
+				return c.Reply(tr(c, "voice.failed_or_empty"))
 			}
 			return processUserInput(c, bot, text, true, voicePrefix)
 		}
 		text, err := transcribeVoice(bot, c.Message().Voice.FileID)
 		if err != nil {
-			return c.Reply(fmt.Sprintf("❌ %v", err))
+			return c.Reply(tr(c, "err.generic", err))
 		}
 		if text == "" {
-			return c.Reply("❌ Transcription produced empty text.")
+			return c.Reply(tr(c, "voice.empty"))
 		}
 		return processUserInput(c, bot, text, true, voicePrefix)
 	})
 }
+
+// applyOtherAnswer stores text as the "Other" answer of a question waiting
+// for input. It is submitted together with the other answers.
+func applyOtherAnswer(c tele.Context, in questionInput, entry *toolNotifyEntry, text string) error {
+	qm := &entry.questions[in.qIdx]
+	qm.customAnswer = text
+	if !qm.multiSelect {
+		qm.selectedOption = -1
+	}
+	editMsg := &tele.Message{ID: in.msgID, Chat: &tele.Chat{ID: entry.chatID}}
+	outbox.edit(editMsg, entry.msgText, rebuildAskMarkup(entry))
+	logger.Info(fmt.Sprintf("AskUserQuestion Other answer: msg_id=%d q=%d text=%s", in.msgID, in.qIdx, truncateStr(text, 200)))
+	return c.Reply(tr(c, "question.other_saved", in.qIdx+1))
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,14 +12,17 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
 	"github.com/Seraphli/tg-cli/internal/pairing"
+	"github.com/Seraphli/tg-cli/internal/route"
 	tele "gopkg.in/telebot.v3"
 )
 
@@ -61,6 +65,44 @@ func scanCustomCommands() map[string]customCmd {
 	return result
 }
 
+// chatLang returns the language for messages sent to chatID: the /bot_lang
+// setting if any, else the Telegram language last seen in that chat.
+func chatLang(chatID int64) string {
+	creds, _ := config.LoadCredentials()
+	if lang := creds.Languages[strconv.FormatInt(chatID, 10)]; lang != "" {
+		return lang
+	}
+	if lang := chatLangs.get(chatID); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
+
+// ctxLang returns the language for replying to an update. A chat setting
+// wins, then the sender's own setting, then the sender's language_code.
+func ctxLang(c tele.Context) string {
+	creds, _ := config.LoadCredentials()
+	chatID := c.Chat().ID
+	if lang := creds.Languages[strconv.FormatInt(chatID, 10)]; lang != "" {
+		return lang
+	}
+	if sender := c.Sender(); sender != nil {
+		if lang := creds.Languages[strconv.FormatInt(sender.ID, 10)]; lang != "" {
+			return lang
+		}
+		chatLangs.observe(chatID, sender.LanguageCode)
+		if lang := i18n.Normalize(sender.LanguageCode); lang != "" {
+			return lang
+		}
+	}
+	return chatLang(chatID)
+}
+
+// tr looks up a catalog message in the language of the update's context.
+func tr(c tele.Context, key string, args ...interface{}) string {
+	return i18n.T(ctxLang(c), key, args...)
+}
+
 // splitBody splits body text into chunks fitting within maxRuneLen.
 // Tries to split at paragraph boundaries (\n\n), then line boundaries (\n),
 // falling back to hard rune-boundary split.
@@ -210,13 +252,20 @@ func readContextUsage(sessionID string) (usedPct int, usedTokens int, windowSize
 }
 
 func sendEventNotification(b *tele.Bot, chat *tele.Chat, chatID, sessionID, event, project, cwd, tmuxTarget, body string) {
-	nd := notify.NotificationData{
-		Event:          event,
-		Project:        project,
-		CWD:            cwd,
-		TmuxTarget:     tmuxTarget,
-		ContextUsedPct: -1,
-	}
+	sendNotification(chat, chatID, sessionID, notify.NotificationData{
+		Event:      event,
+		Project:    project,
+		CWD:        cwd,
+		TmuxTarget: tmuxTarget,
+	}, body)
+}
+
+// sendNotification posts a notification for nd with body, paginating long
+// bodies and filling in the session's context usage.
+func sendNotification(chat *tele.Chat, chatID, sessionID string, nd notify.NotificationData, body string) {
+	event, project, tmuxTarget := nd.Event, nd.Project, nd.TmuxTarget
+	nd.ContextUsedPct = -1
+	nd.Lang = chatLang(chat.ID)
 	if usedPct, usedTokens, windowSize, ok := readContextUsage(sessionID); ok {
 		nd.ContextUsedPct = usedPct
 		nd.ContextUsedTokens = usedTokens
@@ -228,34 +277,43 @@ func sendEventNotification(b *tele.Bot, chat *tele.Chat, chatID, sessionID, even
 	if len(chunks) <= 1 {
 		nd.Body = body
 		text := notify.BuildNotificationText(nd)
-		_, err := b.Send(chat, text)
-		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to send notification: %v", err))
-		} else {
+		track := &outboxTrack{TmuxTarget: tmuxTarget}
+		outbox.postTracked(chat, sessionTopics.thread(chat.ID, sessionID), text, nil, track, func(sent *tele.Message) {
+			msgTargets.record(chat.ID, sent.ID, tmuxTarget)
 			logger.Info(fmt.Sprintf("Notification sent to chat %s: %s [%s] tmux=%s body_len=%d body=%s", chatID, event, project, tmuxTarget, len([]rune(body)), truncateStr(body, 200)))
 			logger.Debug(fmt.Sprintf("TG message sent [%s] full_text:\n%s", event, text))
-		}
+		})
 	} else {
 		nd.Body = chunks[0]
 		nd.Page = 1
 		nd.TotalPages = len(chunks)
 		text := notify.BuildNotificationText(nd)
 		kb := buildPageKeyboard(1, len(chunks))
-		sent, err := b.Send(chat, text, kb)
-		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to send notification: %v", err))
-		} else {
-			pages.store(sent.ID, sessionID, &pageEntry{
+		track := &outboxTrack{
+			TmuxTarget: tmuxTarget,
+			SessionID:  sessionID,
+			Chunks:     chunks,
+			Event:      event,
+			Subject:    nd.Subject,
+			Failed:     nd.Failed,
+			Project:    project,
+			CWD:        nd.CWD,
+		}
+		outbox.postTracked(chat, sessionTopics.thread(chat.ID, sessionID), text, kb, track, func(sent *tele.Message) {
+			msgTargets.record(chat.ID, sent.ID, tmuxTarget)
+			pages.store(msgKey{chat.ID, sent.ID}, sessionID, &pageEntry{
 				chunks:     chunks,
 				event:      event,
+				subject:    nd.Subject,
+				failed:     nd.Failed,
 				project:    project,
-				cwd:        cwd,
+				cwd:        nd.CWD,
 				tmuxTarget: tmuxTarget,
 				chatID:     chat.ID,
 			})
 			logger.Info(fmt.Sprintf("Notification sent to chat %s: %s [%s] tmux=%s (%d pages, msg_id=%d) body_len=%d body=%s", chatID, event, project, tmuxTarget, len(chunks), sent.ID, len([]rune(body)), truncateStr(body, 200)))
 			logger.Debug(fmt.Sprintf("TG message sent [%s] page=1/%d full_text:\n%s", event, len(chunks), text))
-		}
+		})
 	}
 }
 
@@ -292,29 +350,45 @@ func extractTmuxTarget(text string) (*injector.TmuxTarget, error) {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "📟 ") {
 			raw := strings.TrimPrefix(line, "📟 ")
-			target, err := injector.ParseTarget(raw)
-			if err != nil {
-				return nil, err
-			}
-			if target.Socket == "" {
-				if info := sessionState.findInfoByTarget(target.PaneID); info != nil {
-					full, _ := injector.ParseTarget(info.tmuxTarget)
-					if full.Socket != "" {
-						target.Socket = full.Socket
-					}
-				}
-			}
-			return &target, nil
+			return parseTmuxTarget(raw)
 		}
 	}
 	return nil, fmt.Errorf("no tmux target found")
 }
 
-func resolvePermission(msgID int, decision string, suggestionsOverride json.RawMessage) (permDecision, error) {
+// parseTmuxTarget parses a tmux target, restoring a missing socket from sessionState.
+func parseTmuxTarget(raw string) (*injector.TmuxTarget, error) {
+	target, err := injector.ParseTarget(raw)
+	if err != nil {
+		return nil, err
+	}
+	if target.Socket == "" {
+		if info := sessionState.findInfoByTarget(target.PaneID); info != nil {
+			full, _ := injector.ParseTarget(info.tmuxTarget)
+			if full.Socket != "" {
+				target.Socket = full.Socket
+			}
+		}
+	}
+	return &target, nil
+}
+
+// messageTarget returns the tmux target a bot message belongs to: the one
+// recorded when it was sent, else the 📟 line in its text.
+func messageTarget(msg *tele.Message) (*injector.TmuxTarget, error) {
+	if msg.Chat != nil {
+		if raw, ok := msgTargets.get(msg.Chat.ID, msg.ID); ok {
+			return parseTmuxTarget(raw)
+		}
+	}
+	return extractTmuxTarget(msg.Text)
+}
+
+func resolvePermission(chatID int64, msgID int, decision string, suggestionsOverride json.RawMessage) (permDecision, error) {
 	d := permDecision{}
 	suggestions := suggestionsOverride
 	if suggestions == nil {
-		suggestions = pendingPerms.getSuggestions(msgID)
+		suggestions = pendingPerms.getSuggestions(chatID, msgID)
 	}
 	switch {
 	case decision == "allow":
@@ -335,7 +409,7 @@ func resolvePermission(msgID int, decision string, suggestionsOverride json.RawM
 	default:
 		return d, fmt.Errorf("unknown decision: %s", decision)
 	}
-	if !pendingPerms.resolve(msgID, d) {
+	if !pendingPerms.resolve(chatID, msgID, d) {
 		return d, fmt.Errorf("no pending permission for msg_id %d", msgID)
 	}
 	return d, nil
@@ -351,7 +425,12 @@ func buildAnswers(entry *toolNotifyEntry) map[string]string {
 					selected = append(selected, q.optionLabels[oi])
 				}
 			}
+			if q.customAnswer != "" {
+				selected = append(selected, q.customAnswer)
+			}
 			answers[q.questionText] = strings.Join(selected, ", ")
+		} else if q.customAnswer != "" {
+			answers[q.questionText] = q.customAnswer
 		} else if q.selectedOption >= 0 {
 			answers[q.questionText] = q.optionLabels[q.selectedOption]
 		}
@@ -359,7 +438,19 @@ func buildAnswers(entry *toolNotifyEntry) map[string]string {
 	return answers
 }
 
+// askOtherRow returns the ✏️ Other button of question qIdx, showing the typed
+// answer once there is one.
+// Callback unique: "tool", data: "AskUserQuestion|other:<qIdx>".
+func askOtherRow(markup *tele.ReplyMarkup, q questionMeta, qIdx int, lang string) tele.Row {
+	label := fmt.Sprintf("✏️ Q%d: %s", qIdx+1, i18n.T(lang, "question.other"))
+	if q.customAnswer != "" {
+		label = fmt.Sprintf("✅ Q%d: ✏️ %s", qIdx+1, truncateStr(q.customAnswer, 30))
+	}
+	return markup.Row(markup.Data(label, "tool", fmt.Sprintf("AskUserQuestion|other:%d", qIdx)))
+}
+
 func rebuildAskMarkup(entry *toolNotifyEntry) *tele.ReplyMarkup {
+	lang := chatLang(entry.chatID)
 	markup := &tele.ReplyMarkup{}
 	var rows []tele.Row
 
@@ -403,12 +494,13 @@ func rebuildAskMarkup(entry *toolNotifyEntry) *tele.ReplyMarkup {
 				}
 				rows = append(rows, markup.Row(markup.Data(displayLabel, "tool", fmt.Sprintf("AskUserQuestion|%d:%d", qIdx, optIdx))))
 			}
+			rows = append(rows, askOtherRow(markup, q, qIdx, lang))
 		}
 		if hasSubmit {
-			rows = append(rows, markup.Row(markup.Data("📤 Submit", "tool", "AskUserQuestion|submit")))
+			rows = append(rows, markup.Row(markup.Data(i18n.T(lang, "question.submit"), "tool", "AskUserQuestion|submit")))
 		}
 	}
-	rows = append(rows, markup.Row(markup.Data("💬 Chat about this", "tool", "AskUserQuestion|chat")))
+	rows = append(rows, markup.Row(markup.Data(i18n.T(lang, "question.chat"), "tool", "AskUserQuestion|chat")))
 
 	markup.Inline(rows...)
 	return markup
@@ -454,6 +546,9 @@ func buildFrozenMarkup(entry *toolNotifyEntry, footer string) *tele.ReplyMarkup
 				}
 				rows = append(rows, markup.Row(markup.Data(displayLabel, "tool", fmt.Sprintf("AskUserQuestion|%d:%d", qIdx, optIdx))))
 			}
+			if q.customAnswer != "" {
+				rows = append(rows, askOtherRow(markup, q, qIdx, chatLang(entry.chatID)))
+			}
 		}
 	}
 
@@ -465,7 +560,7 @@ func buildFrozenMarkup(entry *toolNotifyEntry, footer string) *tele.ReplyMarkup
 }
 
 // parseSuggestionLabels extracts human-readable labels from suggestion JSON
-func parseSuggestionLabels(suggestionsRaw json.RawMessage) []string {
+func parseSuggestionLabels(suggestionsRaw json.RawMessage, lang string) []string {
 	var suggestions []json.RawMessage
 	json.Unmarshal(suggestionsRaw, &suggestions)
 	var labels []string
@@ -491,7 +586,7 @@ func parseSuggestionLabels(suggestionsRaw json.RawMessage) []string {
 			if len(sug.Directories) > 0 {
 				dir = sug.Directories[0]
 			}
-			label = "✅ Allow dir: " + dir
+			label = "✅ " + i18n.T(lang, "perm.allow_dir", dir)
 		default:
 			toolName := sug.Tool
 			allowPattern := sug.AllowPattern
@@ -501,7 +596,7 @@ func parseSuggestionLabels(suggestionsRaw json.RawMessage) []string {
 					allowPattern = sug.Rules[0].RuleContent
 				}
 			}
-			label = "✅ Always Allow"
+			label = "✅ " + i18n.T(lang, "perm.always_allow")
 			if toolName != "" {
 				label += " " + toolName
 			}
@@ -515,22 +610,26 @@ func parseSuggestionLabels(suggestionsRaw json.RawMessage) []string {
 }
 
 // buildFrozenPermMarkup creates frozen markup for PermissionRequest showing the selected decision.
-func buildFrozenPermMarkup(selectedDecision string, suggestions []string) *tele.ReplyMarkup {
+func buildFrozenPermMarkup(selectedDecision string, suggestions []string, lang string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 	var rows []tele.Row
 
-	allowLabel := "Allow"
-	denyLabel := "Deny"
+	allowLabel := i18n.T(lang, "perm.allow")
+	denyLabel := i18n.T(lang, "perm.deny")
 	if selectedDecision == "allow" {
-		allowLabel = "✅ Allow"
+		allowLabel = "✅ " + allowLabel
 	} else if selectedDecision == "deny" {
-		denyLabel = "✅ Deny"
+		denyLabel = "✅ " + denyLabel
 	}
 
-	rows = append(rows, markup.Row(
+	row1 := []tele.Btn{
 		markup.Data(allowLabel, "perm", "allow"),
 		markup.Data(denyLabel, "perm", "deny"),
-	))
+	}
+	if selectedDecision == "edit" {
+		row1 = append(row1, markup.Data("✅ ✏️ "+i18n.T(lang, "perm.edit"), "perm", "edit"))
+	}
+	rows = append(rows, markup.Row(row1...))
 
 	for i, sug := range suggestions {
 		label := sug
@@ -544,8 +643,8 @@ func buildFrozenPermMarkup(selectedDecision string, suggestions []string) *tele.
 	return markup
 }
 
-func selectToolOption(msgID int, optIdx int) error {
-	entry, ok := toolNotifs.get(msgID)
+func selectToolOption(chatID int64, msgID int, optIdx int) error {
+	entry, ok := toolNotifs.get(chatID, msgID)
 	if !ok {
 		return fmt.Errorf("no tool notification for msg_id %d", msgID)
 	}
@@ -604,6 +703,8 @@ func switchPermMode(t injector.TmuxTarget, targetMode string) (string, error) {
 	if startMode == targetMode {
 		return startMode, nil
 	}
+	// Every BTab press changes the mode shown in the overview
+	defer requestOverviewRefresh()
 	for i := 0; i < 10; i++ {
 		injector.SendKeys(t, "BTab")
 		time.Sleep(500 * time.Millisecond)
@@ -631,17 +732,17 @@ func handlePermCommand(c tele.Context, target injector.TmuxTarget) error {
 	if cmd == "status" {
 		mode, content, err := detectPermMode(target)
 		if err != nil {
-			return c.Reply(fmt.Sprintf("❌ Detect mode failed: %v", err))
+			return c.Reply(tr(c, "permmode.detect_failed", err))
 		}
 		_ = content
-		return c.Reply(fmt.Sprintf("🔐 Current mode: %s", mode))
+		return c.Reply(tr(c, "permmode.current", mode))
 	}
 	// All other values are treated as target mode
 	finalMode, err := switchPermMode(target, cmd)
 	if err != nil {
-		return c.Reply(fmt.Sprintf("❌ Switch failed: %v", err))
+		return c.Reply(tr(c, "permmode.switch_failed", err))
 	}
-	return c.Reply(fmt.Sprintf("🔐 Switched to %s mode", finalMode))
+	return c.Reply(tr(c, "permmode.switched", finalMode))
 }
 
 // handleCaptureCommand handles /bot_capture — captures pane content and replies with it.
@@ -674,17 +775,17 @@ func handleCaptureCommand(c tele.Context, target injector.TmuxTarget) error {
 	logger.Debug(fmt.Sprintf("handleCaptureCommand: target=%v", target))
 	content, err := injector.CapturePane(target)
 	if err != nil {
-		return c.Reply(fmt.Sprintf("❌ Capture failed: %v", err))
+		return c.Reply(tr(c, "capture.failed", err))
 	}
 	logger.Debug(fmt.Sprintf("handleCaptureCommand: captured %d bytes", len(content)))
 	if content == "" {
-		return c.Reply("(empty pane)")
+		return c.Reply(tr(c, "capture.empty"))
 	}
 	content = shortenSeparators(content)
 	const maxRunes = 4000
 	r := []rune(content)
 	if len(r) > maxRunes {
-		content = "...(truncated)\n\n" + string(r[len(r)-maxRunes:])
+		content = tr(c, "capture.truncated") + "\n\n" + string(r[len(r)-maxRunes:])
 	}
 	logger.Debug("handleCaptureCommand: sending reply")
 	return c.Reply(content)
@@ -693,9 +794,9 @@ func handleCaptureCommand(c tele.Context, target injector.TmuxTarget) error {
 // handleEscapeCommand handles /bot_escape — sends Escape key to interrupt Claude Code.
 func handleEscapeCommand(c tele.Context, target injector.TmuxTarget) error {
 	if err := injector.SendKeys(target, "Escape"); err != nil {
-		return c.Reply(fmt.Sprintf("❌ Escape failed: %v", err))
+		return c.Reply(tr(c, "escape.failed", err))
 	}
-	return c.Reply("⏹ Escape sent")
+	return c.Reply(tr(c, "escape.sent"))
 }
 
 func getPaneTitle(tmuxTarget string) string {
@@ -720,17 +821,32 @@ func isSessionRunning(tmuxTarget string) bool {
 
 // hookPayload represents the CC payload enriched by hook.go
 type hookPayload struct {
-	HookEventName   string          `json:"hook_event_name"`
-	SessionID       string          `json:"session_id"`
-	CWD             string          `json:"cwd"`
-	TranscriptPath  string          `json:"transcript_path"`
-	ToolName        string          `json:"tool_name"`
-	ToolInput       json.RawMessage `json:"tool_input"`
-	PermSuggestions json.RawMessage `json:"permission_suggestions"`
-	TmuxTarget      string          `json:"tmux_target"`
-	Project         string          `json:"project"`
+	HookEventName        string          `json:"hook_event_name"`
+	SessionID            string          `json:"session_id"`
+	CWD                  string          `json:"cwd"`
+	TranscriptPath       string          `json:"transcript_path"`
+	ToolName             string          `json:"tool_name"`
+	ToolInput            json.RawMessage `json:"tool_input"`
+	PermSuggestions      json.RawMessage `json:"permission_suggestions"`
+	TmuxTarget           string          `json:"tmux_target"`
+	Project              string          `json:"project"`
+	Route                string          `json:"tg_cli_route"` // TG_CLI_ROUTE of the session
 	Source               string          `json:"source"`
 	LastAssistantMessage string          `json:"last_assistant_message"`
+	// Notification
+	Message          string `json:"message"`
+	NotificationType string `json:"notification_type"`
+	// SubagentStop
+	AgentID             string `json:"agent_id"`
+	AgentType           string `json:"agent_type"`
+	AgentTranscriptPath string `json:"agent_transcript_path"`
+	// PreCompact
+	Trigger            string `json:"trigger"`
+	CustomInstructions string `json:"custom_instructions"`
+	// PostToolUse
+	ToolResponse json.RawMessage `json:"tool_response"`
+	// UserPromptSubmit
+	Prompt string `json:"prompt"`
 }
 
 func parseHookPayload(r *http.Request) (*hookPayload, []byte, error) {
@@ -745,20 +861,19 @@ func parseHookPayload(r *http.Request) (*hookPayload, []byte, error) {
 	return &p, body, nil
 }
 
-func resolveChat(tmuxTarget, cwd string) (*tele.Chat, string) {
+func resolveChat(tmuxTarget, cwd, env string) (*tele.Chat, string) {
 	creds, err := config.LoadCredentials()
 	if err == nil {
-		if cwd != "" && len(creds.ProjectRouteMap) > 0 {
-			if chatID, ok := creds.ProjectRouteMap[cwd]; ok {
+		if chatID, kind, ok := routedChat(&creds, tmuxTarget, cwd, env); ok {
+			switch kind {
+			case "project":
 				logger.Info(fmt.Sprintf("Route resolved: cwd=%s → chat=%d (project route)", cwd, chatID))
-				return &tele.Chat{ID: chatID}, strconv.FormatInt(chatID, 10)
-			}
-		}
-		if tmuxTarget != "" && len(creds.RouteMap) > 0 {
-			if chatID, ok := creds.RouteMap[tmuxTarget]; ok {
+			case "tmux":
 				logger.Info(fmt.Sprintf("Route resolved: tmux=%s → chat=%d (tmux route)", tmuxTarget, chatID))
-				return &tele.Chat{ID: chatID}, strconv.FormatInt(chatID, 10)
+			default:
+				logger.Info(fmt.Sprintf("Route resolved: tmux=%s cwd=%s → chat=%d (%s)", tmuxTarget, cwd, chatID, kind))
 			}
+			return &tele.Chat{ID: chatID}, strconv.FormatInt(chatID, 10)
 		}
 	}
 	chatID := pairing.GetDefaultChatID()
@@ -769,6 +884,54 @@ func resolveChat(tmuxTarget, cwd string) (*tele.Chat, string) {
 	return &tele.Chat{ID: chatIDInt}, chatID
 }
 
+// routedChat returns the chat a session is explicitly routed to: project
+// routes take precedence over tmux routes, then the routing rules are tried
+// in order. kind is "project", "tmux" or "rule <n>" (1-based).
+func routedChat(creds *config.Credentials, tmuxTarget, cwd, env string) (int64, string, bool) {
+	if cwd != "" {
+		if chatID, ok := creds.ProjectRouteMap[cwd]; ok {
+			return chatID, "project", true
+		}
+	}
+	if chatID, _, ok := lookupRouteKey(creds, tmuxTarget); ok {
+		return chatID, "tmux", true
+	}
+	if i, ok := route.Match(creds.RouteRules, routeSession(tmuxTarget, cwd, env)); ok {
+		return creds.RouteRules[i].Chat, fmt.Sprintf("rule %d", i+1), true
+	}
+	return 0, "", false
+}
+
+// routeSession describes a session for the routing rules, looking up git and
+// tmux facts on demand.
+func routeSession(tmuxTarget, cwd, env string) *route.Session {
+	return &route.Session{CWD: cwd, Env: env, Lookup: func(key string) string {
+		switch key {
+		case route.KeyRemote:
+			if cwd != "" {
+				out, _ := gitOutput(cwd, "remote", "get-url", "origin")
+				return strings.TrimSpace(out)
+			}
+		case route.KeyBranch:
+			if cwd != "" {
+				out, _ := gitOutput(cwd, "rev-parse", "--abbrev-ref", "HEAD")
+				return strings.TrimSpace(out)
+			}
+		case route.KeyTmuxSession, route.KeyTmuxWindow:
+			target, err := injector.ParseTarget(tmuxTarget)
+			if tmuxTarget == "" || err != nil {
+				return ""
+			}
+			session, window, _ := injector.GetPaneNames(target)
+			if key == route.KeyTmuxSession {
+				return session
+			}
+			return window
+		}
+		return ""
+	}}
+}
+
 // checkSessionAlive checks if a tmux session still exists; cleans up dead sessions.
 func checkSessionAlive(tmuxTarget string, bot *tele.Bot) bool {
 	target, err := injector.ParseTarget(tmuxTarget)
@@ -788,6 +951,8 @@ func cleanDeadSession(tmuxTarget string, bot *tele.Bot) {
 	if idx := strings.Index(paneID, "@"); idx != -1 {
 		paneID = paneID[:idx]
 	}
+	// Resolve the session's identity before it is forgotten
+	id := sessionIdentity(tmuxTarget)
 	if sid, found := sessionState.findByTarget(tmuxTarget); found {
 		sessionState.remove(sid)
 		pages.cleanupSession(sid)
@@ -798,12 +963,23 @@ func cleanDeadSession(tmuxTarget string, bot *tele.Bot) {
 	if err != nil {
 		return
 	}
-	if chatID, ok := creds.RouteMap[tmuxTarget]; ok {
-		delete(creds.RouteMap, tmuxTarget)
-		config.SaveCredentials(creds)
-		bot.Send(&tele.Chat{ID: chatID}, fmt.Sprintf("⚠️ Session disconnected\n📟 %s\nTmux route auto-unbound.", paneID))
-		logger.Info(fmt.Sprintf("Auto-unbound dead session: tmux=%s chat=%d", tmuxTarget, chatID))
+	// Stable keys outlive the pane; a pane ID key is re-keyed to the pane's
+	// location or the session, and only dropped when neither is known.
+	legacy := id.LegacyKey()
+	chatID, ok := creds.RouteMap[legacy]
+	if !ok {
+		return
 	}
+	if stable := migratedKey(id); stable != "" {
+		rekeyRoute(&creds, legacy, stable)
+		outbox.post(&tele.Chat{ID: chatID}, 0, i18n.T(chatLang(chatID), "session.route_kept", paneID, route.Label(stable)), nil, nil)
+		logger.Info(fmt.Sprintf("Dead session route kept: tmux=%s key=%s chat=%d", tmuxTarget, stable, chatID))
+		return
+	}
+	delete(creds.RouteMap, legacy)
+	config.SaveCredentials(creds)
+	outbox.post(&tele.Chat{ID: chatID}, 0, i18n.T(chatLang(chatID), "session.disconnected", paneID), nil, nil)
+	logger.Info(fmt.Sprintf("Auto-unbound dead session: tmux=%s chat=%d", tmuxTarget, chatID))
 }
 
 // PendingFile represents a pending CC event stored as a file
@@ -820,8 +996,29 @@ type PendingFile struct {
 	CCOutput   json.RawMessage `json:"cc_output"`
 	CreatedAt  string          `json:"created_at"`
 	HookPID    int             `json:"hook_pid"`
+	Reminders  int             `json:"reminders,omitempty"` // timeout reminders sent
+	Expired    bool            `json:"expired,omitempty"`   // deadline passed with action "pending"
+	Copies     []PendingCopy   `json:"copies,omitempty"`    // fan-out copies in other chats
+}
+
+// PendingCopy is a message of a pending request in one chat.
+type PendingCopy struct {
+	ChatID int64 `json:"chat_id"`
+	MsgID  int   `json:"msg_id"`
+}
+
+// messages returns every message of the request: its own, then its copies.
+func (pf *PendingFile) messages() []PendingCopy {
+	if pf.TgMsgID == 0 {
+		return pf.Copies
+	}
+	return append([]PendingCopy{{ChatID: pf.TgChatID, MsgID: pf.TgMsgID}}, pf.Copies...)
 }
 
+// pendingAnswerMu serializes read-modify-write updates of pending files
+// between button answers and the permission timeout loop.
+var pendingAnswerMu sync.Mutex
+
 // pendingDir returns /tmp/<config-dir-basename>/pending, creating it if needed
 func pendingDir() string {
 	base := filepath.Base(config.GetConfigDir())
@@ -856,16 +1053,36 @@ func writePendingFile(path string, pf *PendingFile) error {
 	return os.Rename(tmpPath, path)
 }
 
-// writePendingAnswer updates pending file with answer and status=answered
-func writePendingAnswer(uuid string, ccOutput json.RawMessage) error {
+// errAlreadyAnswered is returned by writePendingAnswer when the request was
+// answered first from another message, e.g. a fan-out copy in another chat.
+var errAlreadyAnswered = errors.New("already answered")
+
+// writePendingAnswer updates pending file with answer and status=answered,
+// then freezes the request's messages other than from, the one it was
+// answered on. A request is only answered once.
+func writePendingAnswer(uuid string, from msgKey, ccOutput json.RawMessage) error {
+	pf, err := answerPendingFile(uuid, ccOutput)
+	if err != nil {
+		return err
+	}
+	go freezeSiblings(pf, from)
+	return nil
+}
+
+func answerPendingFile(uuid string, ccOutput json.RawMessage) (*PendingFile, error) {
+	pendingAnswerMu.Lock()
+	defer pendingAnswerMu.Unlock()
 	path := filepath.Join(pendingDir(), uuid+".json")
 	pf, err := readPendingFile(path)
 	if err != nil {
-		return fmt.Errorf("read pending file: %w", err)
+		return nil, fmt.Errorf("read pending file: %w", err)
+	}
+	if pf.Status == "answered" {
+		return nil, fmt.Errorf("pending request %s: %w", uuid, errAlreadyAnswered)
 	}
 	pf.Status = "answered"
 	pf.CCOutput = ccOutput
-	return writePendingFile(path, pf)
+	return pf, writePendingFile(path, pf)
 }
 
 // isHookAlive checks if the hook process with given PID is still running.
@@ -882,32 +1099,32 @@ func isHookAlive(pid int) bool {
 
 // handleStalePending checks if a pending entry is stale (hook dead or file missing).
 // Returns true if stale (cleanup done), false if still alive.
-func handleStalePending(msgID int, uuid string, bot *tele.Bot) bool {
+func handleStalePending(chatID int64, msgID int, uuid string, bot *tele.Bot) bool {
 	path := filepath.Join(pendingDir(), uuid+".json")
 	pf, err := readPendingFile(path)
 	if err != nil {
-		cleanupPendingState(msgID, uuid, bot, "file missing")
+		cleanupPendingState(chatID, msgID, uuid, bot, "file missing")
 		return true
 	}
 	if pf.Status == "sent" && !isHookAlive(pf.HookPID) {
 		os.Remove(path)
-		cleanupPendingState(msgID, uuid, bot, fmt.Sprintf("hook dead (pid=%d)", pf.HookPID))
+		cleanupPendingState(chatID, msgID, uuid, bot, fmt.Sprintf("hook dead (pid=%d)", pf.HookPID))
 		return true
 	}
 	return false
 }
 
 // cleanupPendingState cleans up bot memory state and freezes TG buttons.
-func cleanupPendingState(msgID int, uuid string, bot *tele.Bot, reason string) {
-	if entry, ok := toolNotifs.get(msgID); ok && !entry.resolved {
-		toolNotifs.markResolved(msgID)
-		editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: entry.chatID}}
-		bot.Edit(editMsg, entry.msgText, buildFrozenMarkup(entry, "❌ Cancelled"))
+func cleanupPendingState(chatID int64, msgID int, uuid string, bot *tele.Bot, reason string) {
+	if entry, ok := toolNotifs.get(chatID, msgID); ok && !entry.resolved {
+		toolNotifs.markResolved(chatID, msgID)
+		editMsg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: chatID}}
+		outbox.edit(editMsg, entry.msgText, buildFrozenMarkup(entry, i18n.T(chatLang(entry.chatID), "common.cancelled")))
 	}
-	if _, ok := pendingPerms.getTarget(msgID); ok {
-		pendingPerms.resolve(msgID, permDecision{Behavior: "deny", Message: "Cancelled (hook dead)"})
+	if _, ok := pendingPerms.getTarget(chatID, msgID); ok {
+		pendingPerms.resolve(chatID, msgID, permDecision{Behavior: "deny", Message: "Cancelled (hook dead)"})
 	}
-	pendingFiles.remove(msgID)
+	pendingFiles.remove(chatID, msgID)
 	logger.Info(fmt.Sprintf("Stale pending cleanup: msg_id=%d uuid=%s reason=%s", msgID, uuid, reason))
 }
 
@@ -933,8 +1150,9 @@ func buildAskCCOutput(payload json.RawMessage, answers map[string]string) json.R
 	return result
 }
 
-// buildPermCCOutput builds CC output for PermissionRequest
-func buildPermCCOutput(decision string, message string, updatedPerms []interface{}) json.RawMessage {
+// buildPermCCOutput builds CC output for PermissionRequest. updatedInput, when
+// set, replaces the tool input of an allowed request.
+func buildPermCCOutput(decision string, message string, updatedPerms []interface{}, updatedInput map[string]interface{}) json.RawMessage {
 	output := map[string]interface{}{
 		"hookSpecificOutput": map[string]interface{}{
 			"hookEventName": "PermissionRequest",
@@ -950,6 +1168,9 @@ func buildPermCCOutput(decision string, message string, updatedPerms []interface
 	if updatedPerms != nil {
 		decisionMap["updatedPermissions"] = updatedPerms
 	}
+	if updatedInput != nil {
+		decisionMap["updatedInput"] = updatedInput
+	}
 	result, _ := json.Marshal(output)
 	return result
 }
@@ -1090,8 +1311,8 @@ func readFirstHumanPrompt(path string) string {
 			continue
 		}
 		var entry struct {
-			Type   string `json:"type"`
-			IsMeta bool   `json:"isMeta"`
+			Type    string `json:"type"`
+			IsMeta  bool   `json:"isMeta"`
 			Message struct {
 				Content json.RawMessage `json:"content"`
 			} `json:"message"`
@@ -1288,26 +1509,26 @@ func readLastAssistantText(path string, maxLen int) string {
 }
 
 // relativeTime formats a time as a human-readable relative string ("Xm ago", "Xh ago", "Xd ago").
-func relativeTime(t time.Time) string {
+func relativeTime(t time.Time, lang string) string {
 	d := time.Since(t)
 	switch {
 	case d < time.Hour:
-		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+		return i18n.T(lang, "time.minutes_ago", int(d.Minutes()))
 	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh ago", int(d.Hours()))
+		return i18n.T(lang, "time.hours_ago", int(d.Hours()))
 	default:
-		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+		return i18n.T(lang, "time.days_ago", int(d.Hours()/24))
 	}
 }
 
 // buildResumeKeyboard builds an inline keyboard with one button per session.
 // Button label: "📝 <prompt truncated to 40> • <relativeTime>".
 // Callback unique: "resume", data: session ID.
-func buildResumeKeyboard(sessions []sessionListEntry) *tele.ReplyMarkup {
+func buildResumeKeyboard(sessions []sessionListEntry, lang string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 	var rows []tele.Row
 	for i, s := range sessions {
-		label := fmt.Sprintf("%d • %s", i+1, relativeTime(s.Modified))
+		label := fmt.Sprintf("%d • %s", i+1, relativeTime(s.Modified, lang))
 		rows = append(rows, markup.Row(markup.Data(label, "resume", s.SessionID)))
 	}
 	markup.Inline(rows...)
@@ -1361,12 +1582,19 @@ func rebuildInMemoryState(bot *tele.Bot, pf *PendingFile, path string) error {
 			qSummaries = append(qSummaries, fmt.Sprintf("%s:[%s]", q.Header, strings.Join(labels, ",")))
 		}
 		contentSummary := strings.Join(qSummaries, " | ")
-		toolNotifs.store(pf.TgMsgID, &toolNotifyEntry{
-			tmuxTarget: pf.TmuxTarget, toolName: "AskUserQuestion",
-			questions: qMetas, chatID: pf.TgChatID, msgText: "",
-			pendingUUID: pf.UUID,
-		})
-		pendingFiles.store(pf.TgMsgID, pf.UUID)
+		for _, m := range pf.messages() {
+			metas := make([]questionMeta, len(qMetas))
+			for i, q := range qMetas {
+				q.selectedOptions = make(map[int]bool)
+				metas[i] = q
+			}
+			toolNotifs.store(m.ChatID, m.MsgID, &toolNotifyEntry{
+				tmuxTarget: pf.TmuxTarget, toolName: "AskUserQuestion",
+				questions: metas, chatID: m.ChatID, msgText: "",
+				pendingUUID: pf.UUID,
+			})
+			pendingFiles.store(m.ChatID, m.MsgID, pf.UUID)
+		}
 		logger.Info(fmt.Sprintf("scanPendingDir: rebuilt AskUserQuestion state: msg_id=%d questions=%d tmux=%s content=%s uuid=%s", pf.TgMsgID, len(askInput.Questions), pf.TmuxTarget, contentSummary, pf.UUID))
 		return nil
 	}
@@ -1374,8 +1602,10 @@ func rebuildInMemoryState(bot *tele.Bot, pf *PendingFile, path string) error {
 	var suggestions []json.RawMessage
 	json.Unmarshal(p.PermSuggestions, &suggestions)
 	suggestionsRaw, _ := json.Marshal(suggestions)
-	pendingPerms.create(pf.TgMsgID, pf.TmuxTarget, suggestionsRaw, "", pf.TgChatID, pf.UUID)
-	pendingFiles.store(pf.TgMsgID, pf.UUID)
+	for _, m := range pf.messages() {
+		pendingPerms.create(m.ChatID, m.MsgID, pf.TmuxTarget, suggestionsRaw, "", pf.UUID)
+		pendingFiles.store(m.ChatID, m.MsgID, pf.UUID)
+	}
 	logger.Info(fmt.Sprintf("scanPendingDir: rebuilt PermissionRequest state: msg_id=%d tool=%s tmux=%s uuid=%s", pf.TgMsgID, pf.ToolName, pf.TmuxTarget, pf.UUID))
 	return nil
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"sync"
 
 	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/injector"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
 	tele "gopkg.in/telebot.v3"
@@ -93,17 +96,33 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 	pf.SessionID = p.SessionID
 	pf.TmuxTarget = p.TmuxTarget
 	pf.ToolName = p.ToolName
-	chat, chatID := resolveChat(p.TmuxTarget, p.CWD)
+	chat, _ := resolveChat(p.TmuxTarget, p.CWD, p.Route)
 	if chat == nil {
 		logger.Info(fmt.Sprintf("No chat for pending request %s, skipping", uuid))
 		return
 	}
+	ensureSessionTopic(bot, chat, &p)
 	// Send intermediate text (PreToolUse Update) before question/permission message
 	if updateBody := processTranscriptUpdates(p.SessionID, p.TranscriptPath); updateBody != "" {
-		chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
-		sendEventNotification(bot, chat, chatID, p.SessionID, "PreToolUse", p.Project, p.CWD, p.TmuxTarget, updateBody)
-		logger.Info(fmt.Sprintf("PreToolUse Update sent for pending request %s (chat=%d)", uuid, chatIDInt))
+		for _, c := range eventChats(bot, chat, &p, "PreToolUse") {
+			sendEventNotification(bot, c, strconv.FormatInt(c.ID, 10), p.SessionID, "PreToolUse", p.Project, p.CWD, p.TmuxTarget, updateBody)
+			logger.Info(fmt.Sprintf("PreToolUse Update sent for pending request %s (chat=%d)", uuid, c.ID))
+		}
+	}
+	event := "PermissionRequest"
+	if p.ToolName == "AskUserQuestion" {
+		event = "AskUserQuestion"
 	}
+	for _, c := range eventChats(bot, chat, &p, event) {
+		sendPendingMessage(c, p, path, pf, uuid)
+	}
+}
+
+// sendPendingMessage sends the question or permission message of a pending
+// request to chat. The first chat's message becomes the request's own, later
+// ones its fan-out copies.
+func sendPendingMessage(chat *tele.Chat, p hookPayload, path string, pf *PendingFile, uuid string) {
+	chatID := strconv.FormatInt(chat.ID, 10)
 	if p.ToolName == "AskUserQuestion" {
 		var askInput struct {
 			Questions []struct {
@@ -140,8 +159,9 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 				Header: q.Header, Question: q.Question, Options: opts, MultiSelect: q.MultiSelect,
 			})
 		}
+		lang := chatLang(chat.ID)
 		text := notify.BuildQuestionText(notify.QuestionData{
-			Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Questions: questionEntries,
+			Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Questions: questionEntries, Lang: lang,
 		})
 		markup := &tele.ReplyMarkup{}
 		var rows []tele.Row
@@ -164,7 +184,7 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 					rows = append(rows, markup.Row(buttons[i]))
 				}
 			}
-			chatBtn := markup.Data("💬 Chat about this", "tool", "AskUserQuestion|chat")
+			chatBtn := markup.Data(i18n.T(lang, "question.chat"), "tool", "AskUserQuestion|chat")
 			rows = append(rows, markup.Row(chatBtn))
 		} else {
 			for qIdx, q := range askInput.Questions {
@@ -175,29 +195,31 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 					}
 					rows = append(rows, markup.Row(markup.Data(label, "tool", fmt.Sprintf("AskUserQuestion|%d:%d", qIdx, optIdx))))
 				}
+				rows = append(rows, askOtherRow(markup, qMetas[qIdx], qIdx, lang))
 			}
 			if hasSubmit {
-				rows = append(rows, markup.Row(markup.Data("📤 Submit", "tool", "AskUserQuestion|submit")))
+				rows = append(rows, markup.Row(markup.Data(i18n.T(lang, "question.submit"), "tool", "AskUserQuestion|submit")))
 			}
-			rows = append(rows, markup.Row(markup.Data("💬 Chat about this", "tool", "AskUserQuestion|chat")))
+			rows = append(rows, markup.Row(markup.Data(i18n.T(lang, "question.chat"), "tool", "AskUserQuestion|chat")))
 		}
 		markup.Inline(rows...)
-		sent, err := bot.Send(chat, text, markup)
+		sent, err := outbox.send(chat, text, &tele.SendOptions{ThreadID: sessionTopics.thread(chat.ID, p.SessionID), ReplyMarkup: markup})
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to send AskUserQuestion: %v", err))
 			return
 		}
 		chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
-		toolNotifs.store(sent.ID, &toolNotifyEntry{
+		msgTargets.record(chatIDInt, sent.ID, p.TmuxTarget)
+		toolNotifs.store(chatIDInt, sent.ID, &toolNotifyEntry{
 			tmuxTarget: p.TmuxTarget, toolName: "AskUserQuestion",
 			questions: qMetas, chatID: chatIDInt, msgText: text,
 			pendingUUID: uuid,
 		})
-		pendingFiles.store(sent.ID, uuid)
-		pf.Status = "sent"
-		pf.TgMsgID = sent.ID
-		pf.TgChatID = chatIDInt
-		writePendingFile(path, pf)
+		pendingFiles.store(chatIDInt, sent.ID, uuid)
+		requestOverviewRefresh()
+		if !recordPendingMessage(path, pf, chatIDInt, sent.ID) {
+			freezePendingMessage(chatIDInt, sent.ID)
+		}
 		logger.Info(fmt.Sprintf("TG question message sent full_text:\n%s", text))
 		var qSummaries []string
 		for _, q := range askInput.Questions {
@@ -211,62 +233,35 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 		logger.Info(fmt.Sprintf("AskUserQuestion sent: msg_id=%d questions=%d tmux=%s content=%s uuid=%s", sent.ID, len(askInput.Questions), p.TmuxTarget, contentSummary, uuid))
 		return
 	}
+	if p.ToolName == "ExitPlanMode" {
+		sendPlanRequest(chat, chatID, p, path, pf, uuid)
+		return
+	}
 	logger.Info(fmt.Sprintf("Permission request: tool=%s project=%s uuid=%s", p.ToolName, p.Project, uuid))
 	var toolInput map[string]interface{}
 	json.Unmarshal(p.ToolInput, &toolInput)
 	logger.Info(fmt.Sprintf("Permission payload: toolInput=%s suggestions=%s", string(p.ToolInput), string(p.PermSuggestions)))
-	text := notify.BuildPermissionText(notify.PermissionData{
+	lang := chatLang(chat.ID)
+	permData := notify.PermissionData{
 		Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget,
-		ToolName: p.ToolName, ToolInput: toolInput,
-	})
+		ToolName: p.ToolName, ToolInput: toolInput, Lang: lang,
+	}
+	if preview, ok := notify.BuildEditPreview(p.ToolName, toolInput); ok {
+		permData.Edit = &preview
+	}
+	text := notify.BuildPermissionText(permData)
 	markup := &tele.ReplyMarkup{}
 	row1 := []tele.Btn{
-		markup.Data("✅ Allow", "perm", "allow"),
-		markup.Data("❌ Deny", "perm", "deny"),
+		markup.Data("✅ "+i18n.T(lang, "perm.allow"), "perm", "allow"),
+		markup.Data("❌ "+i18n.T(lang, "perm.deny"), "perm", "deny"),
+	}
+	if permEditFields[p.ToolName] != "" {
+		row1 = append(row1, markup.Data("✏️ "+i18n.T(lang, "perm.edit"), "perm", "edit"))
 	}
 	var suggestions []json.RawMessage
 	json.Unmarshal(p.PermSuggestions, &suggestions)
 	var row2 []tele.Btn
-	for i, s := range suggestions {
-		var sug struct {
-			Type         string   `json:"type"`
-			Tool         string   `json:"tool"`
-			AllowPattern string   `json:"allow_pattern"`
-			Mode         string   `json:"mode"`
-			Directories  []string `json:"directories"`
-			Rules        []struct {
-				ToolName    string `json:"toolName"`
-				RuleContent string `json:"ruleContent"`
-			} `json:"rules"`
-		}
-		json.Unmarshal(s, &sug)
-		var label string
-		switch sug.Type {
-		case "setMode":
-			label = "✅ " + sug.Mode
-		case "addDirectories":
-			dir := ""
-			if len(sug.Directories) > 0 {
-				dir = sug.Directories[0]
-			}
-			label = "✅ Allow dir: " + dir
-		default:
-			toolName := sug.Tool
-			allowPattern := sug.AllowPattern
-			if toolName == "" && len(sug.Rules) > 0 {
-				toolName = sug.Rules[0].ToolName
-				if allowPattern == "" {
-					allowPattern = sug.Rules[0].RuleContent
-				}
-			}
-			label = "✅ Always Allow"
-			if toolName != "" {
-				label += " " + toolName
-			}
-			if allowPattern != "" && allowPattern != "*" {
-				label += " (" + allowPattern + ")"
-			}
-		}
+	for i, label := range parseSuggestionLabels(p.PermSuggestions, lang) {
 		row2 = append(row2, markup.Data(label, "perm", fmt.Sprintf("s%d", i)))
 	}
 	var permBtnRows []tele.Row
@@ -286,14 +281,14 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 		kb := buildPageKeyboardWithExtra(1, len(permChunks), permBtnRows)
 		markup = kb
 	}
-	sent, err := bot.Send(chat, text, markup)
+	sent, err := outbox.send(chat, text, &tele.SendOptions{ThreadID: sessionTopics.thread(chat.ID, p.SessionID), ReplyMarkup: markup})
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to send permission message: %v", err))
 		return
 	}
 	chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
 	if len(permChunks) > 1 {
-		pages.store(sent.ID, p.SessionID, &pageEntry{
+		pages.store(msgKey{chatIDInt, sent.ID}, p.SessionID, &pageEntry{
 			chunks:     permChunks,
 			event:      "PermissionRequest",
 			project:    p.Project,
@@ -305,13 +300,224 @@ func processPendingRequest(bot *tele.Bot, creds *config.Credentials, uuid string
 	}
 	logger.Info(fmt.Sprintf("Permission request sent: tool=%s project=%s tmux=%s (msg_id=%d pages=%d) uuid=%s", p.ToolName, p.Project, p.TmuxTarget, sent.ID, len(permChunks), uuid))
 	logger.Info(fmt.Sprintf("TG permission message sent full_text:\n%s", text))
+	if permData.Edit != nil && permData.Edit.Truncated {
+		sendDiffAttachment(chat, sent, permData.Edit)
+	}
 	suggestionsRaw, _ := json.Marshal(suggestions)
-	pendingPerms.create(sent.ID, p.TmuxTarget, suggestionsRaw, text, chatIDInt, uuid)
-	pendingFiles.store(sent.ID, uuid)
-	pf.Status = "sent"
-	pf.TgMsgID = sent.ID
-	pf.TgChatID = chatIDInt
-	writePendingFile(path, pf)
+	msgTargets.record(chatIDInt, sent.ID, p.TmuxTarget)
+	pendingPerms.create(chatIDInt, sent.ID, p.TmuxTarget, suggestionsRaw, text, uuid)
+	requestOverviewRefresh()
+	pendingFiles.store(chatIDInt, sent.ID, uuid)
+	if !recordPendingMessage(path, pf, chatIDInt, sent.ID) {
+		freezePendingMessage(chatIDInt, sent.ID)
+	}
+}
+
+// sendDiffAttachment sends the full unified diff of a large edit as a .diff
+// document replying to the permission message.
+func sendDiffAttachment(chat *tele.Chat, replyTo *tele.Message, preview *notify.EditPreview) {
+	tmp, err := os.CreateTemp("", "tg-cli-*.diff")
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create diff attachment: %v", err))
+		return
+	}
+	defer os.Remove(tmp.Name())
+	tmp.WriteString(preview.Unified)
+	tmp.Close()
+	doc := &tele.Document{
+		File:     tele.FromDisk(tmp.Name()),
+		FileName: filepath.Base(preview.FilePath) + ".diff",
+		Caption:  fmt.Sprintf("📝 %s (+%d -%d)", notify.CompressPath(preview.FilePath), preview.Added, preview.Removed),
+	}
+	if _, err := outbox.send(chat, doc, &tele.SendOptions{ReplyTo: replyTo}); err != nil {
+		logger.Error(fmt.Sprintf("Failed to send diff attachment: %v", err))
+	}
+}
+
+// todoUpdates runs checklist updates off the hook path, one at a time per
+// session and chat. Only the latest list waiting for a chat is sent.
+var todoUpdates = struct {
+	sync.Mutex
+	latest  map[string]hookPayload
+	chats   map[string]*tele.Chat
+	running map[string]bool
+}{latest: make(map[string]hookPayload), chats: make(map[string]*tele.Chat), running: make(map[string]bool)}
+
+// queueTodoUpdate schedules updateTodoMessage for the session in p, so a
+// slow or rate-limited chat doesn't hold up the hook response.
+func queueTodoUpdate(chat *tele.Chat, p *hookPayload) {
+	key := p.SessionID + ":" + strconv.FormatInt(chat.ID, 10)
+	todoUpdates.Lock()
+	todoUpdates.latest[key] = *p
+	todoUpdates.chats[key] = chat
+	if todoUpdates.running[key] {
+		todoUpdates.Unlock()
+		return
+	}
+	todoUpdates.running[key] = true
+	todoUpdates.Unlock()
+	go func() {
+		for {
+			todoUpdates.Lock()
+			p, ok := todoUpdates.latest[key]
+			chat := todoUpdates.chats[key]
+			if !ok {
+				delete(todoUpdates.running, key)
+				delete(todoUpdates.chats, key)
+				todoUpdates.Unlock()
+				return
+			}
+			delete(todoUpdates.latest, key)
+			todoUpdates.Unlock()
+			updateTodoMessage(chat, &p)
+		}
+	}()
+}
+
+// updateTodoMessage renders a TodoWrite task list as a checklist, editing the
+// session's existing checklist message in the chat or sending a new one.
+func updateTodoMessage(chat *tele.Chat, p *hookPayload) {
+	var input map[string]interface{}
+	json.Unmarshal(p.ToolInput, &input)
+	todos := notify.ParseTodos(input)
+	text := notify.BuildTodoText(notify.TodoData{
+		Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Todos: todos, Lang: chatLang(chat.ID),
+	})
+	if msgID, ok := todoMsgs.get(p.SessionID, chat.ID); ok {
+		_, err := outbox.edit(&tele.Message{ID: msgID, Chat: chat}, text)
+		if err == nil || errors.Is(err, tele.ErrSameMessageContent) || errors.Is(err, tele.ErrMessageNotModified) {
+			logger.Info(fmt.Sprintf("Todo checklist updated: session=%s msg_id=%d items=%d", p.SessionID, msgID, len(todos)))
+			return
+		}
+		logger.Info(fmt.Sprintf("Todo checklist edit failed, sending new message: msg_id=%d err=%v", msgID, err))
+	}
+	sent, err := outbox.send(chat, text, &tele.SendOptions{ThreadID: sessionTopics.thread(chat.ID, p.SessionID)})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send todo checklist: %v", err))
+		return
+	}
+	todoMsgs.set(p.SessionID, chat.ID, sent.ID)
+	msgTargets.record(chat.ID, sent.ID, p.TmuxTarget)
+	logger.Info(fmt.Sprintf("Todo checklist sent: session=%s msg_id=%d items=%d", p.SessionID, sent.ID, len(todos)))
+}
+
+// checkContextAlert sends a context alert with /compact and /clear buttons when
+// the session's context usage crosses a configured threshold.
+func checkContextAlert(chat *tele.Chat, p *hookPayload) {
+	if p.SessionID == "" || p.TmuxTarget == "" {
+		return
+	}
+	usedPct, usedTokens, windowSize, ok := readContextUsage(p.SessionID)
+	if !ok {
+		return
+	}
+	cfg, _ := config.LoadAppConfig()
+	level := 0
+	for _, t := range cfg.ContextAlertThresholds() {
+		if usedPct >= t {
+			level = t
+		}
+	}
+	if !contextAlerts.update(p.SessionID, level) {
+		return
+	}
+	lang := chatLang(chat.ID)
+	text := notify.BuildContextAlertText(notify.ContextAlertData{
+		Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Threshold: level,
+		ContextUsedPct: usedPct, ContextUsedTokens: usedTokens, ContextWindowSize: windowSize, Lang: lang,
+	})
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data(i18n.T(lang, "context.compact"), "ctx", "compact"),
+		markup.Data(i18n.T(lang, "context.clear"), "ctx", "clear"),
+	))
+	sessionID, tmuxTarget := p.SessionID, p.TmuxTarget
+	outbox.postTracked(chat, sessionTopics.thread(chat.ID, sessionID), text, markup, &outboxTrack{TmuxTarget: tmuxTarget}, func(sent *tele.Message) {
+		msgTargets.record(chat.ID, sent.ID, tmuxTarget)
+		contextAlerts.markMsg(chat.ID, sent.ID, sessionID)
+	})
+	logger.Info(fmt.Sprintf("Context alert queued: session=%s pct=%d threshold=%d", sessionID, usedPct, level))
+}
+
+// briefBodyRunes caps event bodies below the "full" verbosity level.
+const briefBodyRunes = 500
+
+// sendOptionalEvent sends a Notification, SubagentStop, PreCompact or
+// PostToolUse notification as allowed by the event's verbosity setting.
+func sendOptionalEvent(chat *tele.Chat, chatID, event string, p *hookPayload) {
+	cfg, _ := config.LoadAppConfig()
+	level := cfg.EventVerbosity(event)
+	nd := notify.NotificationData{Event: event, Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget}
+	var body string
+	switch event {
+	case "Notification":
+		// Permission prompts already have their own message with buttons
+		if p.NotificationType == "permission_prompt" || strings.HasPrefix(p.Message, "Claude needs your permission") {
+			return
+		}
+		body = p.Message
+	case "SubagentStop":
+		nd.Subject = p.AgentType
+		body = p.LastAssistantMessage
+		if body == "" && p.AgentTranscriptPath != "" {
+			if texts := readAssistantTexts(p.AgentTranscriptPath); len(texts) > 0 {
+				body = texts[len(texts)-1]
+			}
+		}
+	case "PreCompact":
+		nd.Subject = p.Trigger
+		if p.CustomInstructions != "" {
+			body = i18n.T(chatLang(chat.ID), "notify.compact_instructions") + "\n" + p.CustomInstructions
+		}
+	case "PostToolUse":
+		var input map[string]interface{}
+		var resp interface{}
+		json.Unmarshal(p.ToolInput, &input)
+		json.Unmarshal(p.ToolResponse, &resp)
+		result := notify.ParseToolResult(p.ToolName, input, resp)
+		nd.Subject = result.Tool
+		nd.Failed = result.Failed
+		maxOutput := briefBodyRunes
+		if level == config.VerbosityFull {
+			maxOutput = 0
+		}
+		body = result.Body(maxOutput)
+	}
+	if level == config.VerbosityOff || (level == config.VerbosityErrors && !nd.Failed) {
+		logger.Debug(fmt.Sprintf("%s notification skipped (verbosity=%s)", event, level))
+		return
+	}
+	if level != config.VerbosityFull && event != "PostToolUse" {
+		body = truncateStr(body, briefBodyRunes)
+	}
+	sendNotification(chat, chatID, p.SessionID, nd, body)
+}
+
+// mirrorPromptRunes caps mirrored prompts.
+const mirrorPromptRunes = 1000
+
+// mirrorPrompt posts a prompt typed at the terminal as "👤 You: …" when
+// mirrorPrompts is on. Prompts Telegram injected into the pane are skipped.
+func mirrorPrompt(bot *tele.Bot, chat *tele.Chat, p *hookPayload) {
+	prompt := strings.TrimSpace(p.Prompt)
+	if prompt == "" || p.TmuxTarget == "" {
+		return
+	}
+	if cfg, _ := config.LoadAppConfig(); !cfg.MirrorPrompts {
+		return
+	}
+	if t, err := injector.ParseTarget(p.TmuxTarget); err == nil && injector.TakeInjection(t.PaneID, prompt) {
+		logger.Debug(fmt.Sprintf("Prompt not mirrored, injected from Telegram: tmux=%s", p.TmuxTarget))
+		return
+	}
+	tmuxTarget := p.TmuxTarget
+	for _, c := range eventChats(bot, chat, p, "UserPromptSubmit") {
+		text := i18n.T(chatLang(c.ID), "notify.you", truncateStr(prompt, mirrorPromptRunes))
+		outbox.postTracked(c, sessionTopics.thread(c.ID, p.SessionID), text, nil, &outboxTrack{TmuxTarget: tmuxTarget}, func(sent *tele.Message) {
+			msgTargets.record(c.ID, sent.ID, tmuxTarget)
+		})
+		logger.Info(fmt.Sprintf("Prompt mirrored to chat %d: tmux=%s len=%d", c.ID, tmuxTarget, len([]rune(prompt))))
+	}
 }
 
 // registerHTTPHooks registers the main "/hook/" endpoint handler
@@ -346,14 +552,22 @@ func registerHTTPHooks(mux *http.ServeMux, bot *tele.Bot, creds *config.Credenti
 		p.TmuxTarget = notify.FormatPaneID(p.TmuxTarget)
 		// Re-register session on any hook event (survives bot restart)
 		if event != "SessionEnd" && p.SessionID != "" && p.TmuxTarget != "" {
-			sessionState.add(p.SessionID, p.TmuxTarget, p.CWD)
+			sessionState.add(p.SessionID, p.TmuxTarget, p.CWD, p.Route)
+			recordSessionBase(p.SessionID, p.CWD)
+			recordSessionRoot(p.SessionID, p.TmuxTarget, p.TranscriptPath)
 		}
 		if p.SessionID != "" {
 			mu := getHookSessionLock(p.SessionID)
 			mu.Lock()
 			defer mu.Unlock()
 		}
-		chat, chatID := resolveChat(p.TmuxTarget, p.CWD)
+		chat, _ := resolveChat(p.TmuxTarget, p.CWD, p.Route)
+		if chat != nil && event != "SessionEnd" {
+			ensureSessionTopic(bot, chat, p)
+		}
+		if chat != nil && event != "SessionStart" && event != "SessionEnd" {
+			checkContextAlert(chat, p)
+		}
 		switch event {
 		case "SessionStart":
 			if chat == nil || p.TmuxTarget == "" {
@@ -364,28 +578,33 @@ func registerHTTPHooks(mux *http.ServeMux, bot *tele.Bot, creds *config.Credenti
 			if p.Source == "resume" && p.TranscriptPath != "" {
 				body = readLastAssistantText(p.TranscriptPath, 500)
 			}
-			text := notify.BuildNotificationText(notify.NotificationData{
-				Event: "SessionStart", Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Body: body,
-			})
-			bot.Send(chat, text)
-			logger.Info(fmt.Sprintf("Notification sent to chat %s: SessionStart [%s] tmux=%s", chatID, p.Project, p.TmuxTarget))
+			for _, c := range eventChats(bot, chat, p, event) {
+				text := notify.BuildNotificationText(notify.NotificationData{
+					Event: "SessionStart", Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Body: body,
+					Lang: chatLang(c.ID),
+				})
+				outbox.postTracked(c, sessionTopics.thread(c.ID, p.SessionID), text, nil, &outboxTrack{TmuxTarget: p.TmuxTarget}, func(sent *tele.Message) {
+					msgTargets.record(c.ID, sent.ID, p.TmuxTarget)
+				})
+				logger.Info(fmt.Sprintf("Notification queued for chat %d: SessionStart [%s] tmux=%s", c.ID, p.Project, p.TmuxTarget))
+			}
 			if p.SessionID != "" && p.TmuxTarget != "" {
-				sessionState.add(p.SessionID, p.TmuxTarget, p.CWD)
+				sessionState.add(p.SessionID, p.TmuxTarget, p.CWD, p.Route)
 				logger.Info(fmt.Sprintf("Session tracked: %s -> %s", p.SessionID, p.TmuxTarget))
 			}
 		case "SessionEnd":
-			if chat != nil {
-				text := notify.BuildNotificationText(notify.NotificationData{
-					Event: "SessionEnd", Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget,
-				})
-				bot.Send(chat, text)
-				logger.Info(fmt.Sprintf("Notification sent to chat %s: SessionEnd [%s] tmux=%s", chatID, p.Project, p.TmuxTarget))
+			go collectUsage(p.SessionID, p.CWD, p.TranscriptPath)
+			for _, c := range eventChats(bot, chat, p, event) {
+				sendSessionReport(c, strconv.FormatInt(c.ID, 10), p)
+				go closeSessionTopic(bot, c, p.SessionID)
 			}
 			if p.SessionID != "" {
 				sessionState.remove(p.SessionID)
 				logger.Info(fmt.Sprintf("Session untracked: %s", p.SessionID))
 			}
 			pages.cleanupSession(p.SessionID)
+			todoMsgs.cleanupSession(p.SessionID)
+			contextAlerts.cleanupSession(p.SessionID)
 			sessionCounts.cleanup(p.SessionID)
 			cleanPendingFilesBySession(p.SessionID)
 			logger.Info(fmt.Sprintf("Cleaned up session %s", p.SessionID))
@@ -403,9 +622,11 @@ func registerHTTPHooks(mux *http.ServeMux, bot *tele.Bot, creds *config.Credenti
 				reactionTracker.clearAndRemove(bot, p.TmuxTarget)
 				logger.Debug(fmt.Sprintf("Cleared reactions for tmux target: %s", p.TmuxTarget))
 			}
+			mirrorPrompt(bot, chat, p)
 		case "Stop":
 			cancelPendingFilesBySession(p.SessionID)
-			if chat != nil {
+			go collectUsage(p.SessionID, p.CWD, p.TranscriptPath)
+			if chats := eventChats(bot, chat, p, event); len(chats) > 0 {
 				body := p.LastAssistantMessage
 				// Update session count for consistency with PreToolUse
 				if p.SessionID != "" && p.TranscriptPath != "" {
@@ -415,19 +636,36 @@ func registerHTTPHooks(mux *http.ServeMux, bot *tele.Bot, creds *config.Credenti
 					sessionCounts.counts[p.SessionID] = len(texts)
 					lock.Unlock()
 				}
-				sendEventNotification(bot, chat, chatID, p.SessionID, "Stop", p.Project, p.CWD, p.TmuxTarget, body)
+				for _, c := range chats {
+					sendEventNotification(bot, c, strconv.FormatInt(c.ID, 10), p.SessionID, "Stop", p.Project, p.CWD, p.TmuxTarget, body)
+				}
 			}
 		case "PreToolUse":
 			cancelPendingFilesBySession(p.SessionID)
 			// PreToolUse: send intermediate notification
 			// Skip processTranscriptUpdates for AskUserQuestion — /pending/notify handler will call it
 			// to avoid race condition where both paths compete for sessionCounts
-			if chat != nil && p.ToolName != "AskUserQuestion" {
+			chats := eventChats(bot, chat, p, event)
+			if len(chats) > 0 && p.ToolName != "AskUserQuestion" {
 				body := processTranscriptUpdates(p.SessionID, p.TranscriptPath)
 				if body != "" {
-					sendEventNotification(bot, chat, chatID, p.SessionID, "PreToolUse", p.Project, p.CWD, p.TmuxTarget, body)
+					for _, c := range chats {
+						sendEventNotification(bot, c, strconv.FormatInt(c.ID, 10), p.SessionID, "PreToolUse", p.Project, p.CWD, p.TmuxTarget, body)
+					}
 				}
 			}
+			if p.ToolName == "TodoWrite" {
+				for _, c := range chats {
+					queueTodoUpdate(c, p)
+				}
+			}
+		case "Notification", "SubagentStop", "PreCompact", "PostToolUse":
+			if event == "SubagentStop" && p.AgentTranscriptPath != "" {
+				go collectUsage(p.SessionID+"/"+p.AgentID, p.CWD, p.AgentTranscriptPath)
+			}
+			for _, c := range eventChats(bot, chat, p, event) {
+				sendOptionalEvent(c, strconv.FormatInt(c.ID, 10), event, p)
+			}
 		case "PermissionRequest":
 			// PermissionRequest is now handled via file-based communication
 			// hook.go writes pending file and polls for answer
@@ -437,11 +675,16 @@ func registerHTTPHooks(mux *http.ServeMux, bot *tele.Bot, creds *config.Credenti
 			return
 		default:
 			// Unknown event — send notification if possible
-			if chat != nil {
+			if chats := eventChats(bot, chat, p, event); len(chats) > 0 {
 				body := processTranscriptUpdates(p.SessionID, p.TranscriptPath)
-				sendEventNotification(bot, chat, chatID, p.SessionID, event, p.Project, p.CWD, p.TmuxTarget, body)
+				for _, c := range chats {
+					sendEventNotification(bot, c, strconv.FormatInt(c.ID, 10), p.SessionID, event, p.Project, p.CWD, p.TmuxTarget, body)
+				}
 			}
 		}
+		if overviewEvents[event] {
+			requestOverviewRefresh()
+		}
 		w.WriteHeader(200)
 	})
 }
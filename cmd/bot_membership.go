@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/pairing"
+	"github.com/Seraphli/tg-cli/internal/route"
+	tele "gopkg.in/telebot.v3"
+)
+
+// migrateChat moves everything bound to a group that became a supergroup to
+// its new chat ID. It is safe to call more than once for the same move.
+func migrateChat(from, to int64) {
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Chat migration %d → %d: load credentials: %v", from, to, err))
+		return
+	}
+	n := creds.MigrateChat(from, to)
+	if err := config.SaveCredentials(creds); err != nil {
+		logger.Error(fmt.Sprintf("Chat migration %d → %d: save credentials: %v", from, to, err))
+		return
+	}
+	activeSessions.moveChat(from, to)
+	overviews.remove(from)
+	logger.Info(fmt.Sprintf("Chat migrated: %d → %d (%d routes moved)", from, to, n))
+	if n > 0 {
+		outbox.post(&tele.Chat{ID: to}, 0, i18n.N(chatLang(to), "member.migrated", n), nil, nil)
+	}
+	requestOverviewRefresh()
+}
+
+// dropChat forgets a group the bot was removed from and tells the default
+// chat which routes were dropped.
+func dropChat(chat *tele.Chat) {
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Bot removed from chat %d: load credentials: %v", chat.ID, err))
+		return
+	}
+	n := creds.DropChat(chat.ID)
+	if n > 0 {
+		if err := config.SaveCredentials(creds); err != nil {
+			logger.Error(fmt.Sprintf("Bot removed from chat %d: save credentials: %v", chat.ID, err))
+			return
+		}
+	}
+	activeSessions.moveChat(chat.ID, 0)
+	overviews.remove(chat.ID)
+	logger.Info(fmt.Sprintf("Bot removed from chat %d (%s), %d routes dropped", chat.ID, chat.Title, n))
+	defaultChat, _ := strconv.ParseInt(pairing.GetDefaultChatID(), 10, 64)
+	if n == 0 || defaultChat == 0 || defaultChat == chat.ID {
+		return
+	}
+	title := chat.Title
+	if title == "" {
+		title = strconv.FormatInt(chat.ID, 10)
+	}
+	outbox.post(&tele.Chat{ID: defaultChat}, 0, i18n.N(chatLang(defaultChat), "member.removed", n, title), nil, nil)
+}
+
+// buildJoinMessage lists the running sessions with a button each to route
+// them to the group the bot just joined.
+// Callback unique: "join", data: "<index>".
+func buildJoinMessage(chatID int64, lang string) (string, *tele.ReplyMarkup, []string) {
+	creds, _ := config.LoadCredentials()
+	var targets []string
+	for _, info := range sessionState.all() {
+		if info.tmuxTarget != "" && isSessionRunning(info.tmuxTarget) {
+			targets = append(targets, info.tmuxTarget)
+		}
+	}
+	text := i18n.T(lang, "member.added")
+	if len(targets) == 0 {
+		return text + "\n\n" + i18n.T(lang, "member.no_sessions"), nil, nil
+	}
+	sort.Strings(targets)
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for i, t := range targets {
+		label := sessionLabel(t)
+		if id, _, ok := lookupRouteKey(&creds, t); ok && id == chatID {
+			label = "✅ " + label
+		}
+		rows = append(rows, tele.Row{markup.Data(label, "join", strconv.Itoa(i))})
+	}
+	markup.Inline(rows...)
+	return text, markup, targets
+}
+
+// registerMembershipHandlers handles group migration and the bot being
+// added to or removed from groups.
+func registerMembershipHandlers(bot *tele.Bot) {
+	bot.Handle(tele.OnMigration, func(c tele.Context) error {
+		from, to := c.Migration()
+		migrateChat(from, to)
+		return nil
+	})
+
+	bot.Handle(tele.OnMyChatMember, func(c tele.Context) error {
+		u := c.ChatMember()
+		if u == nil || u.Chat == nil || u.NewChatMember == nil || u.OldChatMember == nil {
+			return nil
+		}
+		if u.Chat.Type != tele.ChatGroup && u.Chat.Type != tele.ChatSuperGroup {
+			return nil
+		}
+		gone := func(r tele.MemberStatus) bool { return r == tele.Left || r == tele.Kicked }
+		switch {
+		case gone(u.NewChatMember.Role) && !gone(u.OldChatMember.Role):
+			dropChat(u.Chat)
+		case !gone(u.NewChatMember.Role) && gone(u.OldChatMember.Role):
+			logger.Info(fmt.Sprintf("Bot added to chat %d (%s)", u.Chat.ID, u.Chat.Title))
+			lang := chatLang(u.Chat.ID)
+			// Only list sessions to a paired user; anyone can add the bot
+			if u.Sender == nil || !pairing.IsAllowed(strconv.FormatInt(u.Sender.ID, 10)) {
+				outbox.post(u.Chat, 0, i18n.T(lang, "member.added_unpaired"), nil, nil)
+				return nil
+			}
+			text, markup, targets := buildJoinMessage(u.Chat.ID, lang)
+			if markup == nil {
+				outbox.post(u.Chat, 0, text, nil, nil)
+				return nil
+			}
+			sent, err := outbox.send(u.Chat, text, markup)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to send binding helper: %v", err))
+				return nil
+			}
+			joinViews.set(u.Chat.ID, sent.ID, targets)
+		}
+		return nil
+	})
+
+	bot.Handle(&tele.InlineButton{Unique: "join"}, func(c tele.Context) error {
+		if !pairing.IsAllowed(strconv.FormatInt(c.Sender().ID, 10)) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "err.not_paired")})
+		}
+		targets, ok := joinViews.get(c.Chat().ID, c.Message().ID)
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
+		}
+		idx, err := strconv.Atoi(c.Data())
+		if err != nil || idx < 0 || idx >= len(targets) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+		}
+		t := targets[idx]
+		if !checkSessionAlive(t, bot) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
+		}
+		creds, err := config.LoadCredentials()
+		if err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "err.load_config", err)})
+		}
+		// Bind to where the pane is now, not a cached location
+		refreshPaneLocation(t)
+		key := sessionIdentity(t).PaneRouteKey()
+		unbindRouteKeys(&creds, t)
+		creds.RouteMap[key] = c.Chat().ID
+		if err := config.SaveCredentials(creds); err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "err.save", err)})
+		}
+		logger.Info(fmt.Sprintf("Route bound (join): tmux=%s key=%s → chat=%d", t, key, c.Chat().ID))
+		requestOverviewRefresh()
+		text, markup, nt := buildJoinMessage(c.Chat().ID, ctxLang(c))
+		joinViews.set(c.Chat().ID, c.Message().ID, nt)
+		if _, err := outbox.edit(c.Message(), text, markup); err != nil {
+			logger.Debug(fmt.Sprintf("binding helper edit error: %v", err))
+		}
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "member.bound", route.Label(key))})
+	})
+}
@@ -0,0 +1,524 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Telegram limits: ~30 messages/s overall, ~1 message/s per private chat
+// and 20 messages/min per group. Buckets allow short bursts on top of that.
+const (
+	outboxGlobalRate    = 30.0
+	outboxPrivateRate   = 1.0
+	outboxPrivateBurst  = 3.0
+	outboxGroupRate     = 20.0 / 60.0
+	outboxGroupBurst    = 10.0
+	outboxSyncAttempts  = 3
+	outboxPostAttempts  = 60
+	outboxMaxNetBackoff = time.Minute
+)
+
+// tokenBucket is a minimal blocking rate limiter. Tokens may go negative,
+// which reserves future slots for callers already waiting.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(capacity, rate float64) *tokenBucket {
+	return &tokenBucket{capacity: capacity, tokens: capacity, rate: rate, last: time.Now()}
+}
+
+// take blocks until a token is available.
+func (tb *tokenBucket) take() {
+	tb.mu.Lock()
+	now := time.Now()
+	tb.tokens = math.Min(tb.capacity, tb.tokens+now.Sub(tb.last).Seconds()*tb.rate)
+	tb.last = now
+	tb.tokens--
+	var wait time.Duration
+	if tb.tokens < 0 {
+		wait = time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+	}
+	tb.mu.Unlock()
+	time.Sleep(wait)
+}
+
+// penalize drains the bucket so the next token is available only after d.
+func (tb *tokenBucket) penalize(d time.Duration) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.last = time.Now()
+	tb.tokens = math.Min(tb.tokens, 0) - d.Seconds()*tb.rate
+}
+
+// outboxItem is one queued Telegram call. Fire-and-forget text messages are
+// persisted (ID/ChatID/ThreadID/Text/Markup) until delivered; synchronous calls carry
+// a closure and a done channel instead.
+type outboxItem struct {
+	ID        string          `json:"id"`
+	ChatID    int64           `json:"chat_id"`
+	ThreadID  int             `json:"thread_id,omitempty"`
+	Text      string          `json:"text"`
+	Markup    json.RawMessage `json:"markup,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"attempts,omitempty"`
+	Track     *outboxTrack    `json:"track,omitempty"`
+
+	run    func() (*tele.Message, error)
+	done   chan outboxResult
+	onSent func(*tele.Message)
+}
+
+// outboxTrack is what a restored message needs to be tracked after delivery,
+// since onSent does not survive a restart: the pane replies go to and, for
+// paginated notifications, the pages.
+type outboxTrack struct {
+	TmuxTarget string   `json:"tmux_target,omitempty"`
+	SessionID  string   `json:"session_id,omitempty"`
+	Chunks     []string `json:"chunks,omitempty"`
+	Event      string   `json:"event,omitempty"`
+	Subject    string   `json:"subject,omitempty"`
+	Failed     bool     `json:"failed,omitempty"`
+	Project    string   `json:"project,omitempty"`
+	CWD        string   `json:"cwd,omitempty"`
+}
+
+// apply records a restored message the way its lost onSent would have.
+func (t *outboxTrack) apply(chatID int64, sent *tele.Message) {
+	if t.TmuxTarget != "" {
+		msgTargets.record(chatID, sent.ID, t.TmuxTarget)
+	}
+	if len(t.Chunks) > 1 {
+		pages.store(msgKey{chatID, sent.ID}, t.SessionID, &pageEntry{
+			chunks:     t.Chunks,
+			event:      t.Event,
+			subject:    t.Subject,
+			failed:     t.Failed,
+			project:    t.Project,
+			cwd:        t.CWD,
+			tmuxTarget: t.TmuxTarget,
+			chatID:     chatID,
+		})
+	}
+}
+
+type outboxResult struct {
+	msg *tele.Message
+	err error
+}
+
+// chatQueue is the FIFO for one chat. A single worker drains it, which keeps
+// messages of a session in order.
+type chatQueue struct {
+	mu     sync.Mutex
+	items  []*outboxItem
+	wake   chan struct{}
+	bucket *tokenBucket
+}
+
+// outboxQueue serializes all outbound Telegram traffic per chat and applies
+// per-chat and global rate limits, retrying 429s after retry_after.
+type outboxQueue struct {
+	mu     sync.Mutex
+	bot    *tele.Bot
+	queues map[int64]*chatQueue
+	global *tokenBucket
+	seq    int64
+}
+
+var outbox = &outboxQueue{
+	queues: make(map[int64]*chatQueue),
+	global: newTokenBucket(outboxGlobalRate, outboxGlobalRate),
+}
+
+// outboxDir returns <config-dir>/outbox, creating it if needed.
+func outboxDir() string {
+	dir := filepath.Join(config.GetConfigDir(), "outbox")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// start attaches the bot and re-queues messages left over from a previous run.
+func (o *outboxQueue) start(bot *tele.Bot) {
+	o.mu.Lock()
+	o.bot = bot
+	o.mu.Unlock()
+	dir := outboxDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var it outboxItem
+		if err := json.Unmarshal(data, &it); err != nil || it.ChatID == 0 {
+			logger.Error(fmt.Sprintf("Outbox: dropping unreadable item %s: %v", name, err))
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		o.enqueue(&it)
+	}
+	if len(names) > 0 {
+		logger.Info(fmt.Sprintf("Outbox: restored %d unsent message(s)", len(names)))
+	}
+}
+
+func (o *outboxQueue) queueFor(chatID int64) *chatQueue {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	q, ok := o.queues[chatID]
+	if ok {
+		return q
+	}
+	bucket := newTokenBucket(outboxPrivateBurst, outboxPrivateRate)
+	if chatID < 0 {
+		bucket = newTokenBucket(outboxGroupBurst, outboxGroupRate)
+	}
+	q = &chatQueue{wake: make(chan struct{}, 1), bucket: bucket}
+	o.queues[chatID] = q
+	go o.worker(q)
+	return q
+}
+
+func (o *outboxQueue) enqueue(it *outboxItem) {
+	q := o.queueFor(it.ChatID)
+	q.mu.Lock()
+	q.items = append(q.items, it)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *outboxQueue) worker(q *chatQueue) {
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			<-q.wake
+			continue
+		}
+		it := q.items[0]
+		q.items = q.items[1:]
+		q.mu.Unlock()
+		msg, err := o.deliver(q, it)
+		if it.run == nil {
+			os.Remove(filepath.Join(outboxDir(), it.ID+".json"))
+			if err != nil {
+				logger.Error(fmt.Sprintf("Outbox: dropped message to chat %d: %v", it.ChatID, err))
+			} else if it.onSent != nil {
+				it.onSent(msg)
+			} else if it.Track != nil {
+				it.Track.apply(it.ChatID, msg)
+			}
+		}
+		if it.done != nil {
+			it.done <- outboxResult{msg: msg, err: err}
+		}
+	}
+}
+
+// deliver performs the call, waiting out 429s and retrying network errors
+// and Telegram 5xx responses. Other errors returned by Telegram are final.
+// Persisted messages retry up to outboxPostAttempts times, counted across
+// restarts.
+func (o *outboxQueue) deliver(q *chatQueue, it *outboxItem) (*tele.Message, error) {
+	for attempt := 1; ; attempt++ {
+		q.bucket.take()
+		o.global.take()
+		msg, err := o.attempt(it)
+		if err == nil {
+			return msg, nil
+		}
+		var flood tele.FloodError
+		if errors.As(err, &flood) {
+			wait := time.Duration(flood.RetryAfter) * time.Second
+			logger.Info(fmt.Sprintf("Outbox: rate limited in chat %d, retrying after %s", it.ChatID, wait))
+			q.bucket.penalize(wait)
+			continue
+		}
+		var groupErr tele.GroupError
+		if errors.As(err, &groupErr) && groupErr.MigratedTo != 0 {
+			// Missed the migration update; move the chat's routes now
+			go migrateChat(it.ChatID, groupErr.MigratedTo)
+		}
+		if code := telegramErrorCode(err); code != 0 && code < 500 {
+			return nil, err
+		}
+		if it.run != nil && attempt >= outboxSyncAttempts {
+			return nil, err
+		}
+		if it.run == nil {
+			it.Attempts++
+			if it.Attempts >= outboxPostAttempts {
+				return nil, fmt.Errorf("giving up after %d attempts: %w", it.Attempts, err)
+			}
+			o.persist(it)
+		}
+		backoff := time.Duration(attempt) * 2 * time.Second
+		if backoff > outboxMaxNetBackoff {
+			backoff = outboxMaxNetBackoff
+		}
+		logger.Error(fmt.Sprintf("Outbox: send to chat %d failed (attempt %d), retrying in %s: %v", it.ChatID, attempt, backoff, err))
+		time.Sleep(backoff)
+	}
+}
+
+// telegramErrorCode returns the error code of a response Telegram rejected,
+// or 0 when the request did not get an answer. telebot returns errors it has
+// no value for as plain "telegram: <description> (<code>)" errors.
+func telegramErrorCode(err error) int {
+	var tgErr *tele.Error
+	if errors.As(err, &tgErr) {
+		return tgErr.Code
+	}
+	var groupErr tele.GroupError
+	if errors.As(err, &groupErr) {
+		return http.StatusBadRequest
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "telegram: ") {
+		return 0
+	}
+	if i := strings.LastIndex(msg, " ("); i != -1 && strings.HasSuffix(msg, ")") {
+		if code, err := strconv.Atoi(msg[i+2 : len(msg)-1]); err == nil {
+			return code
+		}
+	}
+	return http.StatusBadRequest
+}
+
+func (o *outboxQueue) attempt(it *outboxItem) (*tele.Message, error) {
+	if it.run != nil {
+		return it.run()
+	}
+	opts := &tele.SendOptions{ThreadID: it.ThreadID}
+	if len(it.Markup) > 0 {
+		var markup tele.ReplyMarkup
+		if err := json.Unmarshal(it.Markup, &markup); err == nil {
+			opts.ReplyMarkup = &markup
+		}
+	}
+	return o.bot.Send(&tele.Chat{ID: it.ChatID}, it.Text, opts)
+}
+
+// call queues fn behind everything already pending for chatID and waits for it.
+func (o *outboxQueue) call(chatID int64, fn func() (*tele.Message, error)) (*tele.Message, error) {
+	it := &outboxItem{ChatID: chatID, run: fn, done: make(chan outboxResult, 1)}
+	o.enqueue(it)
+	res := <-it.done
+	return res.msg, res.err
+}
+
+// send is the queued equivalent of bot.Send.
+func (o *outboxQueue) send(to *tele.Chat, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	if to == nil {
+		return nil, tele.ErrBadRecipient
+	}
+	return o.call(to.ID, func() (*tele.Message, error) {
+		return o.bot.Send(to, what, cloneOpts(opts)...)
+	})
+}
+
+// edit is the queued equivalent of bot.Edit.
+func (o *outboxQueue) edit(msg tele.Editable, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	_, chatID := msg.MessageSig()
+	return o.call(chatID, func() (*tele.Message, error) {
+		return o.bot.Edit(msg, what, cloneOpts(opts)...)
+	})
+}
+
+// do runs an arbitrary API call (reactions, raw methods) through the chat's queue.
+func (o *outboxQueue) do(chatID int64, fn func() error) error {
+	_, err := o.call(chatID, func() (*tele.Message, error) {
+		return nil, fn()
+	})
+	return err
+}
+
+// post queues a text message without waiting for it. The message is written
+// to disk first so it survives Telegram outages and bot restarts; onSent (if
+// set) runs after delivery in this process. A non-zero threadID posts into
+// that forum topic.
+func (o *outboxQueue) post(to *tele.Chat, threadID int, text string, markup *tele.ReplyMarkup, onSent func(*tele.Message)) {
+	o.postTracked(to, threadID, text, markup, nil, onSent)
+}
+
+// postTracked is post for session messages. If the bot restarts before
+// delivery, track stands in for onSent so replies and page buttons still
+// work; anything else onSent does (logging, context alert marks) is skipped.
+func (o *outboxQueue) postTracked(to *tele.Chat, threadID int, text string, markup *tele.ReplyMarkup, track *outboxTrack, onSent func(*tele.Message)) {
+	if to == nil {
+		return
+	}
+	o.mu.Lock()
+	o.seq++
+	seq := o.seq
+	o.mu.Unlock()
+	it := &outboxItem{
+		ID:        fmt.Sprintf("%019d-%06d", time.Now().UnixNano(), seq),
+		ChatID:    to.ID,
+		ThreadID:  threadID,
+		Text:      text,
+		CreatedAt: time.Now(),
+		Track:     track,
+		onSent:    onSent,
+	}
+	if markup != nil {
+		it.Markup, _ = json.Marshal(markup)
+	}
+	o.persist(it)
+	o.enqueue(it)
+}
+
+// persist writes a posted message to the outbox directory.
+func (o *outboxQueue) persist(it *outboxItem) {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(outboxDir(), it.ID+".json"), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Outbox: failed to persist message for chat %d: %v", it.ChatID, err))
+	}
+}
+
+// cloneOpts deep-copies reply markups so a retry doesn't resend buttons whose
+// callback data telebot already prefixed in place.
+func cloneOpts(opts []interface{}) []interface{} {
+	out := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		switch v := opt.(type) {
+		case *tele.ReplyMarkup:
+			out[i] = cloneMarkup(v)
+		case *tele.SendOptions:
+			cp := *v
+			cp.ReplyMarkup = cloneMarkup(v.ReplyMarkup)
+			out[i] = &cp
+		default:
+			out[i] = opt
+		}
+	}
+	return out
+}
+
+func cloneMarkup(m *tele.ReplyMarkup) *tele.ReplyMarkup {
+	if m == nil {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	var cp tele.ReplyMarkup
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return m
+	}
+	return &cp
+}
+
+// queuedContext sends a handler's replies and edits through the outbox so
+// command output shares the chat's rate limit with notifications. Callback
+// answers are not chat messages and still go out directly.
+type queuedContext struct {
+	tele.Context
+}
+
+// queuedReplies is the middleware that hands handlers a queuedContext.
+func queuedReplies(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		return next(queuedContext{c})
+	}
+}
+
+// queue runs fn through the chat's queue, or directly when the update has
+// no chat.
+func (c queuedContext) queue(fn func() error) error {
+	chat := c.Chat()
+	if chat == nil {
+		return fn()
+	}
+	return outbox.do(chat.ID, fn)
+}
+
+func (c queuedContext) Send(what interface{}, opts ...interface{}) error {
+	return c.queue(func() error { return c.Context.Send(what, cloneOpts(opts)...) })
+}
+
+func (c queuedContext) SendAlbum(a tele.Album, opts ...interface{}) error {
+	return c.queue(func() error { return c.Context.SendAlbum(a, cloneOpts(opts)...) })
+}
+
+func (c queuedContext) Reply(what interface{}, opts ...interface{}) error {
+	if c.Message() == nil {
+		return tele.ErrBadContext
+	}
+	return c.queue(func() error { return c.Context.Reply(what, cloneOpts(opts)...) })
+}
+
+func (c queuedContext) Edit(what interface{}, opts ...interface{}) error {
+	if c.Callback() == nil && c.InlineResult() == nil {
+		return tele.ErrBadContext
+	}
+	return c.queue(func() error { return c.Context.Edit(what, cloneOpts(opts)...) })
+}
+
+func (c queuedContext) EditCaption(caption string, opts ...interface{}) error {
+	if c.Callback() == nil && c.InlineResult() == nil {
+		return tele.ErrBadContext
+	}
+	return c.queue(func() error { return c.Context.EditCaption(caption, cloneOpts(opts)...) })
+}
+
+func (c queuedContext) EditOrSend(what interface{}, opts ...interface{}) error {
+	if err := c.Edit(what, opts...); err != tele.ErrBadContext {
+		return err
+	}
+	return c.Send(what, opts...)
+}
+
+func (c queuedContext) EditOrReply(what interface{}, opts ...interface{}) error {
+	if err := c.Edit(what, opts...); err != tele.ErrBadContext {
+		return err
+	}
+	return c.Reply(what, opts...)
+}
+
+func (c queuedContext) Delete() error {
+	if c.Message() == nil {
+		return tele.ErrBadContext
+	}
+	return c.queue(c.Context.Delete)
+}
+
+func (c queuedContext) Forward(msg tele.Editable, opts ...interface{}) error {
+	return c.queue(func() error { return c.Context.Forward(msg, cloneOpts(opts)...) })
+}
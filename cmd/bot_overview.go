@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/injector"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/pairing"
+	tele "gopkg.in/telebot.v3"
+)
+
+// overviewMinInterval spaces out overview refreshes triggered by bursts of hook events.
+const overviewMinInterval = 2 * time.Second
+
+var overviewKick = make(chan struct{}, 1)
+
+// overviewEvents are the hook events that change what the overview shows.
+// Pending requests and mode switches refresh it where they happen.
+var overviewEvents = map[string]bool{
+	"SessionStart":     true,
+	"SessionEnd":       true,
+	"UserPromptSubmit": true,
+	"Stop":             true,
+}
+
+// requestOverviewRefresh schedules a refresh of the pinned chat overviews.
+func requestOverviewRefresh() {
+	select {
+	case overviewKick <- struct{}{}:
+	default:
+	}
+}
+
+// startOverviewLoop refreshes the pinned overviews whenever a refresh is requested.
+func startOverviewLoop(ctx context.Context, bot *tele.Bot) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-overviewKick:
+			refreshOverviews(bot)
+			time.Sleep(overviewMinInterval)
+		}
+	}
+}
+
+// refreshOverviews rebuilds the overview of every chat with routed sessions,
+// and of chats that already have one.
+func refreshOverviews(bot *tele.Bot) {
+	cfg, _ := config.LoadAppConfig()
+	if !cfg.OverviewEnabled() {
+		return
+	}
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		return
+	}
+	defaultChat, _ := strconv.ParseInt(pairing.GetDefaultChatID(), 10, 64)
+	byChat := make(map[int64][]notify.OverviewSession)
+	activeByChat := make(map[int64]string)
+	for sid, info := range sessionState.all() {
+		chatID, _, ok := routedChat(&creds, info.tmuxTarget, info.cwd, info.route)
+		if !ok {
+			chatID = defaultChat
+		}
+		if chatID == 0 {
+			continue
+		}
+		s := notify.OverviewSession{
+			TmuxTarget:     info.tmuxTarget,
+			Project:        filepath.Base(info.cwd),
+			CWD:            info.cwd,
+			Running:        isSessionRunning(info.tmuxTarget),
+			ContextUsedPct: -1,
+			Pending:        pendingPerms.countByTarget(info.tmuxTarget) + toolNotifs.countPendingByTarget(info.tmuxTarget),
+			LastEvent:      info.lastEvent,
+		}
+		if target, err := injector.ParseTarget(info.tmuxTarget); err == nil {
+			if mode, _, err := detectPermMode(target); err == nil {
+				s.PermMode = mode
+			}
+		}
+		if pct, _, _, ok := readContextUsage(sid); ok {
+			s.ContextUsedPct = pct
+		}
+		active, seen := activeByChat[chatID]
+		if !seen {
+			if key, perUser, ok := activeSessions.get(chatID, 0); ok && !perUser {
+				active = notify.FormatPaneID(routeKeyTarget(key))
+			}
+			activeByChat[chatID] = active
+		}
+		s.Active = active != "" && active == notify.FormatPaneID(info.tmuxTarget)
+		byChat[chatID] = append(byChat[chatID], s)
+	}
+	for _, chatID := range overviews.chats() {
+		if _, ok := byChat[chatID]; !ok {
+			byChat[chatID] = nil
+		}
+	}
+	for chatID, sessions := range byChat {
+		sort.Slice(sessions, func(i, j int) bool { return sessions[i].TmuxTarget < sessions[j].TmuxTarget })
+		updateOverview(bot, chatID, sessions)
+	}
+}
+
+// updateOverview edits the chat's overview message, or sends and pins a new
+// one when there is none or it can no longer be edited.
+func updateOverview(bot *tele.Bot, chatID int64, sessions []notify.OverviewSession) {
+	text := notify.BuildOverviewText(notify.OverviewData{Sessions: sessions, Updated: time.Now(), Lang: chatLang(chatID)})
+	chat := &tele.Chat{ID: chatID}
+	msgID, last, ok := overviews.get(chatID)
+	if ok && text == last {
+		return
+	}
+	if ok {
+		_, err := outbox.edit(&tele.Message{ID: msgID, Chat: chat}, text)
+		if err == nil || errors.Is(err, tele.ErrSameMessageContent) || errors.Is(err, tele.ErrMessageNotModified) {
+			overviews.set(chatID, msgID, text)
+			return
+		}
+		logger.Info(fmt.Sprintf("Overview edit failed, sending new message: chat=%d msg_id=%d err=%v", chatID, msgID, err))
+	}
+	sent, err := outbox.send(chat, text, tele.Silent)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send overview to chat %d: %v", chatID, err))
+		return
+	}
+	overviews.set(chatID, sent.ID, text)
+	if err := outbox.do(chatID, func() error { return bot.Pin(sent, tele.Silent) }); err != nil {
+		logger.Info(fmt.Sprintf("Overview pin failed (chat=%d): %v", chatID, err))
+	}
+	logger.Info(fmt.Sprintf("Overview sent: chat=%d msg_id=%d sessions=%d", chatID, sent.ID, len(sessions)))
+}
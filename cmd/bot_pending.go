@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	tele "gopkg.in/telebot.v3"
+)
+
+// livePendingFile returns the pending file of a request if its hook is still
+// waiting for an answer.
+func livePendingFile(uuid string) (*PendingFile, bool) {
+	pf, err := readPendingFile(filepath.Join(pendingDir(), uuid+".json"))
+	if err != nil || pf.Status != "sent" || !isHookAlive(pf.HookPID) {
+		return nil, false
+	}
+	return pf, true
+}
+
+// collectPending gathers every unanswered permission request, plan and question
+// across sessions, grouped by pane. Requests whose hook is gone are skipped.
+func collectPending(lang string) ([]notify.PendingSession, *pendingView) {
+	byPane := make(map[string]*notify.PendingSession)
+	perms := make(map[string][]msgKey)
+	session := func(tmuxTarget string, p hookPayload) *notify.PendingSession {
+		key := notify.FormatPaneID(tmuxTarget)
+		s, ok := byPane[key]
+		if !ok {
+			s = &notify.PendingSession{TmuxTarget: tmuxTarget, Project: p.Project, CWD: p.CWD}
+			byPane[key] = s
+		}
+		return s
+	}
+	created := func(pf *PendingFile) time.Time {
+		t, _ := time.Parse(time.RFC3339Nano, pf.CreatedAt)
+		return t
+	}
+	// Fan-out copies of a request share its uuid; list each request once
+	seen := make(map[string]bool)
+	for _, k := range pendingPerms.keys() {
+		uuid, ok := pendingPerms.getUUID(k.chatID, k.msgID)
+		if !ok {
+			uuid, ok = pendingFiles.get(k.chatID, k.msgID)
+		}
+		if !ok || seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		pf, ok := livePendingFile(uuid)
+		if !ok {
+			continue
+		}
+		var p hookPayload
+		json.Unmarshal(pf.Payload, &p)
+		var input map[string]interface{}
+		json.Unmarshal(p.ToolInput, &input)
+		tmuxTarget, _ := pendingPerms.getTarget(k.chatID, k.msgID)
+		s := session(tmuxTarget, p)
+		// Plans are listed but left out of the batch buttons, which would
+		// approve them without choosing a mode
+		if pf.ToolName == "ExitPlanMode" {
+			s.Items = append(s.Items, notify.PendingItem{Summary: i18n.T(lang, "pending.plan"), Plan: true, Created: created(pf)})
+			continue
+		}
+		s.Items = append(s.Items, notify.PendingItem{Summary: notify.ToolSummary(pf.ToolName, input), Created: created(pf)})
+		key := notify.FormatPaneID(tmuxTarget)
+		perms[key] = append(perms[key], k)
+	}
+	for _, k := range toolNotifs.unresolved() {
+		entry, ok := toolNotifs.get(k.chatID, k.msgID)
+		if !ok || seen[entry.pendingUUID] {
+			continue
+		}
+		seen[entry.pendingUUID] = true
+		pf, ok := livePendingFile(entry.pendingUUID)
+		if !ok {
+			continue
+		}
+		var p hookPayload
+		json.Unmarshal(pf.Payload, &p)
+		summary := i18n.T(lang, "pending.question")
+		if len(entry.questions) > 0 {
+			summary += ": " + entry.questions[0].questionText
+		}
+		s := session(entry.tmuxTarget, p)
+		s.Items = append(s.Items, notify.PendingItem{Summary: summary, Question: true, Created: created(pf)})
+	}
+	keys := make([]string, 0, len(byPane))
+	for key := range byPane {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sessions := make([]notify.PendingSession, 0, len(keys))
+	view := &pendingView{}
+	for _, key := range keys {
+		sessions = append(sessions, *byPane[key])
+		view.targets = append(view.targets, byPane[key].TmuxTarget)
+		view.perms = append(view.perms, perms[key])
+	}
+	return sessions, view
+}
+
+// buildPendingMessage renders the /bot_pending list with one approve button
+// per session that has permission requests and a deny-all button.
+// Callback unique: "pend", data: "a<session index>" or "d".
+func buildPendingMessage(lang string) (string, *tele.ReplyMarkup, *pendingView) {
+	sessions, view := collectPending(lang)
+	text := notify.BuildPendingText(notify.PendingData{Sessions: sessions, Lang: lang})
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for i, ids := range view.perms {
+		if len(ids) == 0 {
+			continue
+		}
+		label := i18n.N(lang, "pending.approve_session", len(ids), i+1, len(ids))
+		rows = append(rows, tele.Row{markup.Data(label, "pend", "a"+strconv.Itoa(i))})
+	}
+	if len(rows) == 0 {
+		return text, nil, view
+	}
+	rows = append(rows, tele.Row{markup.Data(i18n.T(lang, "pending.deny_all"), "pend", "d")})
+	markup.Inline(rows...)
+	return text, markup, view
+}
+
+// answerPermission answers a pending permission request the way its own
+// buttons would and freezes the original message. It returns false when the
+// request was already answered or its hook is gone.
+func answerPermission(bot *tele.Bot, k msgKey, decision string) bool {
+	chatID, msgID := k.chatID, k.msgID
+	uuid, uuidOk := pendingPerms.getUUID(chatID, msgID)
+	if !uuidOk {
+		uuid, uuidOk = pendingFiles.get(chatID, msgID)
+	}
+	if !uuidOk {
+		return false
+	}
+	if _, ok := livePendingFile(uuid); !ok {
+		return false
+	}
+	lang := chatLang(chatID)
+	msgText := pendingPerms.getMsgText(chatID, msgID)
+	sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(chatID, msgID), lang)
+	d, err := resolvePermission(chatID, msgID, decision, nil)
+	if err != nil {
+		return false
+	}
+	if err := writePendingAnswer(uuid, k, buildPermCCOutput(d.Behavior, d.Message, nil, nil)); errors.Is(err, errAlreadyAnswered) {
+		showAnsweredElsewhere(chatID, msgID, msgText)
+		return false
+	} else if err != nil {
+		logger.Error(fmt.Sprintf("Failed to write pending answer for perm: %v", err))
+	}
+	msg := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: chatID}}
+	markup := buildFrozenPermMarkup(decision, sugLabels, lang)
+	if msgText != "" {
+		outbox.edit(msg, msgText, markup)
+	} else {
+		outbox.do(chatID, func() error {
+			_, err := bot.EditReplyMarkup(msg, markup)
+			return err
+		})
+	}
+	return true
+}
+
+// handlePendingCommand handles /bot_pending — lists unanswered requests of
+// all sessions with batch approve and deny buttons.
+func handlePendingCommand(c tele.Context) error {
+	lang := ctxLang(c)
+	text, markup, view := buildPendingMessage(lang)
+	sent, err := outbox.send(c.Chat(), text, markup)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send pending list: %v", err))
+		return nil
+	}
+	pendingViews.set(c.Chat().ID, sent.ID, view)
+	logger.Info(fmt.Sprintf("Pending list sent: sessions=%d msg_id=%d", len(view.targets), sent.ID))
+	return nil
+}
+
+// registerPendingCallbacks handles the batch buttons of /bot_pending.
+func registerPendingCallbacks(bot *tele.Bot) {
+	bot.Handle(&tele.InlineButton{Unique: "pend"}, func(c tele.Context) error {
+		view, ok := pendingViews.get(c.Chat().ID, c.Message().ID)
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
+		}
+		data := c.Data()
+		decision := "deny"
+		var ids []msgKey
+		switch {
+		case data == "d":
+			for _, sessionIDs := range view.perms {
+				ids = append(ids, sessionIDs...)
+			}
+		case len(data) > 1 && data[0] == 'a':
+			idx, err := strconv.Atoi(data[1:])
+			if err != nil || idx < 0 || idx >= len(view.perms) {
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+			}
+			decision = "allow"
+			ids = view.perms[idx]
+		default:
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+		}
+		n := 0
+		for _, k := range ids {
+			if answerPermission(bot, k, decision) {
+				n++
+			}
+		}
+		if n > 0 {
+			requestOverviewRefresh()
+		}
+		logger.Info(fmt.Sprintf("Pending batch resolved: decision=%s requested=%d answered=%d", decision, len(ids), n))
+		lang := ctxLang(c)
+		text, markup, nv := buildPendingMessage(lang)
+		pendingViews.set(c.Chat().ID, c.Message().ID, nv)
+		if _, err := outbox.edit(c.Message(), text, markup); err != nil {
+			logger.Debug(fmt.Sprintf("pending list edit error: %v", err))
+		}
+		return c.Respond(&tele.CallbackResponse{Text: i18n.N(lang, "pending.done_"+decision, n, n)})
+	})
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	tele "gopkg.in/telebot.v3"
+)
+
+// permEditFields maps the tools whose permission requests can be edited
+// before allowing to the tool input field the ✏️ Edit button replaces.
+var permEditFields = map[string]string{
+	"Bash":  "command",
+	"Write": "content",
+	"Edit":  "new_string",
+}
+
+const (
+	// maxEditPromptValue bounds the current value quoted in the edit prompt.
+	maxEditPromptValue = 3000
+	// maxEditNoticeValue bounds each of the original and edited values shown
+	// on the frozen permission message.
+	maxEditNoticeValue = 800
+)
+
+// pendingPermInput returns the pending file and tool input of a permission
+// request that is still waiting for an answer.
+func pendingPermInput(chatID int64, msgID int) (string, *PendingFile, map[string]interface{}, bool) {
+	uuid, ok := pendingPerms.getUUID(chatID, msgID)
+	if !ok {
+		uuid, ok = pendingFiles.get(chatID, msgID)
+	}
+	if !ok {
+		return "", nil, nil, false
+	}
+	if _, ok := pendingPerms.getTarget(chatID, msgID); !ok {
+		return "", nil, nil, false
+	}
+	pf, ok := livePendingFile(uuid)
+	if !ok {
+		return "", nil, nil, false
+	}
+	var p hookPayload
+	json.Unmarshal(pf.Payload, &p)
+	input := map[string]interface{}{}
+	json.Unmarshal(p.ToolInput, &input)
+	return uuid, pf, input, true
+}
+
+// handlePermEditButton asks for the replacement value of an editable
+// permission request. The request stays pending until the prompt is answered.
+func handlePermEditButton(c tele.Context) error {
+	msgID := c.Message().ID
+	_, pf, input, ok := pendingPermInput(c.Chat().ID, msgID)
+	if !ok || permEditFields[pf.ToolName] == "" {
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired_or_invalid")})
+	}
+	field := permEditFields[pf.ToolName]
+	current, _ := input[field].(string)
+	text := tr(c, "perm.edit_prompt", field) + "\n\n" + truncateStr(current, maxEditPromptValue)
+	sent, err := outbox.send(c.Chat(), text, &tele.SendOptions{
+		ReplyTo:     c.Message(),
+		ReplyMarkup: &tele.ReplyMarkup{ForceReply: true, Selective: true},
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send permission edit prompt: %v", err))
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "err.generic", err)})
+	}
+	permEditPrompts.set(c.Chat().ID, sent.ID, msgID)
+	logger.Info(fmt.Sprintf("Permission edit requested: msg_id=%d tool=%s field=%s prompt_id=%d", msgID, pf.ToolName, field, sent.ID))
+	return c.Respond()
+}
+
+// applyPermEdit allows the permission request with the edited field replaced
+// by text, and freezes the permission message showing both values.
+func applyPermEdit(c tele.Context, bot *tele.Bot, permMsgID int, text string) error {
+	uuid, pf, input, ok := pendingPermInput(c.Chat().ID, permMsgID)
+	if !ok {
+		return c.Reply(tr(c, "perm.edit_expired"))
+	}
+	field := permEditFields[pf.ToolName]
+	original, _ := input[field].(string)
+	input[field] = text
+	lang := chatLang(c.Chat().ID)
+	msgText := pendingPerms.getMsgText(c.Chat().ID, permMsgID)
+	sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(c.Chat().ID, permMsgID), lang)
+	d := permDecision{Behavior: "allow"}
+	if !pendingPerms.resolve(c.Chat().ID, permMsgID, d) {
+		return c.Reply(tr(c, "perm.edit_expired"))
+	}
+	if err := writePendingAnswer(uuid, msgKey{c.Chat().ID, permMsgID}, buildPermCCOutput(d.Behavior, "", nil, input)); errors.Is(err, errAlreadyAnswered) {
+		showAnsweredElsewhere(c.Chat().ID, permMsgID, msgText)
+		return c.Reply(tr(c, "perm.elsewhere"))
+	} else if err != nil {
+		logger.Error(fmt.Sprintf("Failed to write pending answer for perm: %v", err))
+	}
+	notice := i18n.T(lang, "perm.edited", field) +
+		"\n\n" + i18n.T(lang, "perm.edit_original") + "\n" + truncateStr(original, maxEditNoticeValue) +
+		"\n\n" + i18n.T(lang, "perm.edit_new") + "\n" + truncateStr(text, maxEditNoticeValue)
+	markup := buildFrozenPermMarkup("edit", sugLabels, lang)
+	msg := &tele.Message{ID: permMsgID, Chat: c.Chat()}
+	if full := msgText + "\n\n" + notice; msgText != "" && len([]rune(full)) <= 4000 {
+		outbox.edit(msg, full, markup)
+	} else {
+		outbox.do(c.Chat().ID, func() error {
+			_, err := bot.EditReplyMarkup(msg, markup)
+			return err
+		})
+		outbox.send(c.Chat(), notice, &tele.SendOptions{ReplyTo: msg})
+	}
+	reactAndTrack(bot, c.Chat(), c.Message(), pf.TmuxTarget)
+	logger.Info(fmt.Sprintf("Permission allowed with edited input: msg_id=%d tool=%s field=%s uuid=%s value=%s", permMsgID, pf.ToolName, field, uuid, truncateStr(text, 200)))
+	return nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	tele "gopkg.in/telebot.v3"
+)
+
+// permTimeoutInterval is how often unanswered permission requests are checked.
+const permTimeoutInterval = 30 * time.Second
+
+// startPermissionTimeoutLoop sends reminders for unanswered permission
+// requests and applies the configured default action at their deadline.
+func startPermissionTimeoutLoop(ctx context.Context, bot *tele.Bot) {
+	ticker := time.NewTicker(permTimeoutInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkPermissionTimeouts(bot)
+		}
+	}
+}
+
+func checkPermissionTimeouts(bot *tele.Bot) {
+	cfg, _ := config.LoadAppConfig()
+	dir := pendingDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		pf, err := readPendingFile(path)
+		if err != nil || pf.Status != "sent" || pf.Expired || pf.TgMsgID == 0 || pf.ToolName == "AskUserQuestion" || pf.ToolName == "ExitPlanMode" {
+			continue
+		}
+		if !isHookAlive(pf.HookPID) {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339Nano, pf.CreatedAt)
+		if err != nil {
+			continue
+		}
+		var p hookPayload
+		json.Unmarshal(pf.Payload, &p)
+		policy := cfg.PermissionTimeoutFor(p.CWD)
+		age := time.Since(created)
+		if policy.Deadline > 0 && age >= time.Duration(policy.Deadline)*time.Minute {
+			applyPermissionDeadline(bot, path, pf, policy, age)
+			continue
+		}
+		if policy.RemindEvery > 0 && age >= time.Duration(policy.RemindEvery*(pf.Reminders+1))*time.Minute {
+			sendPermissionReminder(path, pf, policy, age)
+		}
+	}
+}
+
+// updatePendingFile re-reads a pending file under the answer lock and applies
+// fn if the request is still waiting for an answer.
+func updatePendingFile(path string, fn func(pf *PendingFile)) bool {
+	pendingAnswerMu.Lock()
+	defer pendingAnswerMu.Unlock()
+	pf, err := readPendingFile(path)
+	if err != nil || pf.Status != "sent" {
+		return false
+	}
+	fn(pf)
+	return writePendingFile(path, pf) == nil
+}
+
+// sendPermissionReminder re-pings a waiting permission request by replying
+// to its message, mentioning the configured users.
+func sendPermissionReminder(path string, pf *PendingFile, policy config.PermissionTimeout, age time.Duration) {
+	if !updatePendingFile(path, func(pf *PendingFile) { pf.Reminders++ }) {
+		return
+	}
+	lang := chatLang(pf.TgChatID)
+	lines := []string{i18n.T(lang, "perm.reminder", notify.FormatDuration(age))}
+	if policy.Deadline > 0 {
+		left := time.Duration(policy.Deadline)*time.Minute - age
+		lines = append(lines, i18n.T(lang, "perm.deadline_"+policy.DeadlineAction(), notify.FormatDuration(left)))
+	}
+	if len(policy.Mention) > 0 {
+		lines = append(lines, strings.Join(policy.Mention, " "))
+	}
+	chat := &tele.Chat{ID: pf.TgChatID}
+	replyTo := &tele.Message{ID: pf.TgMsgID, Chat: chat}
+	if _, err := outbox.send(chat, strings.Join(lines, "\n"), &tele.SendOptions{ReplyTo: replyTo}); err != nil {
+		logger.Error(fmt.Sprintf("Failed to send permission reminder: uuid=%s err=%v", pf.UUID, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Permission reminder sent: uuid=%s msg_id=%d age=%s", pf.UUID, pf.TgMsgID, age.Round(time.Second)))
+}
+
+// applyPermissionDeadline answers an expired permission request with the
+// policy's default action, or marks it expired when the action is pending,
+// and freezes its Telegram message.
+func applyPermissionDeadline(bot *tele.Bot, path string, pf *PendingFile, policy config.PermissionTimeout, age time.Duration) {
+	msgID, chatID := pf.TgMsgID, pf.TgChatID
+	lang := chatLang(chatID)
+	chat := &tele.Chat{ID: chatID}
+	msg := &tele.Message{ID: msgID, Chat: chat}
+	waited := notify.FormatDuration(age)
+	action := policy.DeadlineAction()
+	if action == config.PermActionPending {
+		if !updatePendingFile(path, func(pf *PendingFile) { pf.Expired = true }) {
+			return
+		}
+		outbox.send(chat, i18n.T(lang, "perm.timeout_pending", waited), &tele.SendOptions{ReplyTo: msg})
+		logger.Info(fmt.Sprintf("Permission deadline passed, left pending: uuid=%s msg_id=%d", pf.UUID, msgID))
+		return
+	}
+	d := permDecision{Behavior: action}
+	if action == config.PermActionDeny {
+		d.Message = policy.Message
+		if d.Message == "" {
+			d.Message = fmt.Sprintf("Permission request timed out after %s without an answer.", waited)
+		}
+	}
+	answered := updatePendingFile(path, func(pf *PendingFile) {
+		pf.Status = "answered"
+		pf.CCOutput = buildPermCCOutput(d.Behavior, d.Message, nil, nil)
+	})
+	if !answered {
+		return
+	}
+	go freezeSiblings(pf, msgKey{chatID, msgID})
+	sugLabels := parseSuggestionLabels(pendingPerms.getSuggestions(chatID, msgID), lang)
+	msgText := pendingPerms.getMsgText(chatID, msgID)
+	pendingPerms.resolve(chatID, msgID, d)
+	pendingFiles.remove(chatID, msgID)
+	requestOverviewRefresh()
+	markup := buildFrozenPermMarkup(action, sugLabels, lang)
+	notice := i18n.T(lang, "perm.timeout_"+action, waited)
+	if msgText != "" {
+		outbox.edit(msg, msgText+"\n\n"+notice, markup)
+	} else {
+		outbox.do(chatID, func() error {
+			_, err := bot.EditReplyMarkup(msg, markup)
+			return err
+		})
+		outbox.send(chat, notice, &tele.SendOptions{ReplyTo: msg})
+	}
+	logger.Info(fmt.Sprintf("Permission deadline applied: uuid=%s msg_id=%d action=%s age=%s", pf.UUID, msgID, action, age.Round(time.Second)))
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	tele "gopkg.in/telebot.v3"
+)
+
+// planChoices maps the plan approval buttons to the permission mode the
+// session continues in. "keep" denies the request so Claude keeps planning.
+var planChoices = map[string]string{
+	"auto":   "acceptEdits",
+	"manual": "default",
+	"keep":   "",
+}
+
+// buildPlanRows returns the plan approval buttons, marking selected once a
+// choice was made.
+// Callback unique: "plan", data: "auto", "manual" or "keep".
+func buildPlanRows(lang, selected string) []tele.Row {
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for _, choice := range []string{"auto", "manual", "keep"} {
+		label := i18n.T(lang, "plan."+choice)
+		if choice == selected {
+			label = "✅ " + label
+		}
+		rows = append(rows, markup.Row(markup.Data(label, "plan", choice)))
+	}
+	return rows
+}
+
+// planDecision returns the hook decision for a plan approval button.
+func planDecision(choice string) permDecision {
+	mode := planChoices[choice]
+	if mode == "" {
+		return permDecision{Behavior: "deny", Message: "The user wants to keep planning and will reply with feedback on the plan."}
+	}
+	d := permDecision{Behavior: "allow"}
+	d.UpdatedPermissions, _ = json.Marshal([]map[string]string{{"type": "setMode", "mode": mode, "destination": "session"}})
+	return d
+}
+
+// sendPlanRequest sends an ExitPlanMode permission request as the full plan,
+// paginated, with the plan approval buttons.
+func sendPlanRequest(chat *tele.Chat, chatID string, p hookPayload, path string, pf *PendingFile, uuid string) {
+	var input struct {
+		Plan string `json:"plan"`
+	}
+	json.Unmarshal(p.ToolInput, &input)
+	lang := chatLang(chat.ID)
+	header := notify.BuildPlanHeader(notify.PlanData{
+		Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Lang: lang,
+	})
+	plan := strings.TrimSpace(input.Plan)
+	if plan == "" {
+		plan = i18n.T(lang, "plan.empty")
+	}
+	// Leave room for the header and the "📄 n/m" footer on every page
+	chunks := splitBody(plan, 3900-len([]rune(header))-16)
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = header + "\n\n" + chunk
+		if len(chunks) > 1 {
+			texts[i] += fmt.Sprintf("\n\n📄 %d/%d", i+1, len(chunks))
+		}
+	}
+	rows := buildPlanRows(lang, "")
+	var markup *tele.ReplyMarkup
+	if len(texts) > 1 {
+		markup = buildPageKeyboardWithExtra(1, len(texts), rows)
+	} else {
+		markup = &tele.ReplyMarkup{}
+		markup.Inline(rows...)
+	}
+	sent, err := outbox.send(chat, texts[0], &tele.SendOptions{ThreadID: sessionTopics.thread(chat.ID, p.SessionID), ReplyMarkup: markup})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send plan approval: %v", err))
+		return
+	}
+	chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
+	if len(texts) > 1 {
+		pages.store(msgKey{chatIDInt, sent.ID}, p.SessionID, &pageEntry{chunks: texts, raw: true, permRows: rows, tmuxTarget: p.TmuxTarget, chatID: chatIDInt})
+	}
+	logger.Info(fmt.Sprintf("Plan approval sent: project=%s tmux=%s (msg_id=%d pages=%d) uuid=%s", p.Project, p.TmuxTarget, sent.ID, len(texts), uuid))
+	var suggestions []json.RawMessage
+	json.Unmarshal(p.PermSuggestions, &suggestions)
+	suggestionsRaw, _ := json.Marshal(suggestions)
+	msgTargets.record(chatIDInt, sent.ID, p.TmuxTarget)
+	pendingPerms.create(chatIDInt, sent.ID, p.TmuxTarget, suggestionsRaw, texts[0], uuid)
+	requestOverviewRefresh()
+	pendingFiles.store(chatIDInt, sent.ID, uuid)
+	if !recordPendingMessage(path, pf, chatIDInt, sent.ID) {
+		freezePendingMessage(chatIDInt, sent.ID)
+	}
+}
+
+// registerPlanCallbacks handles the plan approval buttons.
+func registerPlanCallbacks(bot *tele.Bot) {
+	bot.Handle(&tele.InlineButton{Unique: "plan"}, func(c tele.Context) error {
+		choice := c.Data()
+		if _, ok := planChoices[choice]; !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+		}
+		chatID, msgID := c.Chat().ID, c.Message().ID
+		permTarget, ok := pendingPerms.getTarget(chatID, msgID)
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired_or_invalid")})
+		}
+		if permTarget != "" && !checkSessionAlive(permTarget, bot) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
+		}
+		uuid, uuidOk := pendingPerms.getUUID(chatID, msgID)
+		if !uuidOk {
+			uuid, uuidOk = pendingFiles.get(chatID, msgID)
+		}
+		d := planDecision(choice)
+		if !pendingPerms.resolve(chatID, msgID, d) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired_or_invalid")})
+		}
+		if uuidOk {
+			var updatedPerms []interface{}
+			if d.UpdatedPermissions != nil {
+				json.Unmarshal(d.UpdatedPermissions, &updatedPerms)
+			}
+			if err := writePendingAnswer(uuid, msgKey{chatID, msgID}, buildPermCCOutput(d.Behavior, d.Message, updatedPerms, nil)); errors.Is(err, errAlreadyAnswered) {
+				showAnsweredElsewhere(chatID, msgID, c.Message().Text)
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "perm.elsewhere")})
+			} else if err != nil {
+				logger.Error(fmt.Sprintf("Failed to write pending answer for plan: %v", err))
+			}
+		}
+		requestOverviewRefresh()
+		lang := ctxLang(c)
+		frozen := &tele.ReplyMarkup{}
+		frozen.Inline(buildPlanRows(lang, choice)...)
+		outbox.edit(c.Message(), c.Message().Text, frozen)
+		if choice == "keep" {
+			outbox.send(c.Chat(), i18n.T(lang, "plan.keep_hint"), &tele.SendOptions{ReplyTo: c.Message()})
+		}
+		logger.Info(fmt.Sprintf("Plan resolved via TG button: msg_id=%d choice=%s uuid=%s", msgID, choice, uuid))
+		if permTarget != "" {
+			reactAndTrack(bot, c.Message().Chat, c.Message(), permTarget)
+		}
+		return c.Respond(&tele.CallbackResponse{Text: i18n.T(lang, "cb.decided", i18n.T(lang, "plan."+choice))})
+	})
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/injector"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/report"
+	tele "gopkg.in/telebot.v3"
+)
+
+// recordSessionBase remembers the commit a session's working directory is at
+// the first time the session is seen. Sessions outside git are recorded with
+// an empty commit so they are not probed again.
+func recordSessionBase(sessionID, cwd string) {
+	if sessionID == "" || cwd == "" || sessionBases.has(sessionID) {
+		return
+	}
+	var commit string
+	if out, err := gitOutput(cwd, "rev-parse", "HEAD"); err == nil {
+		commit = strings.TrimSpace(out)
+	}
+	sessionBases.record(sessionBase{SessionID: sessionID, CWD: cwd, Commit: commit})
+	logger.Info(fmt.Sprintf("Session base recorded: session=%s commit=%s", sessionID, commit))
+}
+
+// diffRevision is the revision a session's changes are diffed against.
+func diffRevision(base sessionBase) string {
+	if base.Commit == "" {
+		return "HEAD"
+	}
+	return base.Commit
+}
+
+// sessionDiff runs git diff with args in dir, counting files created since
+// the session started. Untracked files that aren't ignored are added as
+// intent-to-add to a copy of the index, so the real index is left alone.
+func sessionDiff(dir string, args ...string) (string, error) {
+	indexPath, err := gitOutput(dir, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return gitOutput(dir, append([]string{"diff"}, args...)...)
+	}
+	indexPath = strings.TrimSpace(indexPath)
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(dir, indexPath)
+	}
+	tmp, err := os.CreateTemp("", "tg-cli-index-*")
+	if err != nil {
+		return gitOutput(dir, append([]string{"diff"}, args...)...)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if data, err := os.ReadFile(indexPath); err == nil {
+		os.WriteFile(tmp.Name(), data, 0600)
+	} else {
+		os.Remove(tmp.Name()) // no index yet; git creates one
+	}
+	env := []string{"GIT_INDEX_FILE=" + tmp.Name()}
+	if _, err := gitOutputEnv(dir, env, "add", "--intent-to-add", "--", ":/"); err != nil {
+		logger.Debug(fmt.Sprintf("Session diff: adding untracked files failed in %s: %v", dir, err))
+	}
+	return gitOutputEnv(dir, env, append([]string{"diff"}, args...)...)
+}
+
+// sendSessionReport sends the SessionEnd notification with a summary of the
+// session built from its transcript and the git changes since it started.
+func sendSessionReport(chat *tele.Chat, chatID string, p *hookPayload) {
+	var summary report.Summary
+	if p.TranscriptPath != "" {
+		if f, err := os.Open(p.TranscriptPath); err == nil {
+			summary = report.Summarize(f)
+			f.Close()
+		}
+	}
+	data := notify.SessionReportData{
+		Project: p.Project, CWD: p.CWD, TmuxTarget: p.TmuxTarget, Summary: summary, Lang: chatLang(chat.ID),
+	}
+	if p.CWD != "" {
+		recordSessionBase(p.SessionID, p.CWD)
+		if _, err := gitOutput(p.CWD, "rev-parse", "--is-inside-work-tree"); err == nil {
+			base, _ := sessionBases.get(p.SessionID)
+			data.Git = true
+			data.BaseCommit = base.Commit
+			data.DiffStat, _ = sessionDiff(p.CWD, "--stat=60", diffRevision(base))
+		}
+	}
+	text := notify.BuildSessionReportText(data)
+	var markup *tele.ReplyMarkup
+	if p.SessionID != "" {
+		m := &tele.ReplyMarkup{}
+		var btns []tele.Btn
+		if strings.TrimSpace(data.DiffStat) != "" {
+			btns = append(btns, m.Data(i18n.T(data.Lang, "report.diff"), "end_diff", p.SessionID))
+		}
+		if p.TmuxTarget != "" {
+			btns = append(btns, m.Data(i18n.T(data.Lang, "report.resume"), "end_resume", p.SessionID))
+		}
+		if len(btns) > 0 {
+			m.Inline(m.Row(btns...))
+			markup = m
+		}
+	}
+	outbox.postTracked(chat, sessionTopics.thread(chat.ID, p.SessionID), text, markup, &outboxTrack{TmuxTarget: p.TmuxTarget}, func(sent *tele.Message) {
+		msgTargets.record(chat.ID, sent.ID, p.TmuxTarget)
+	})
+	logger.Info(fmt.Sprintf("Notification queued for chat %s: SessionEnd [%s] tmux=%s", chatID, p.Project, p.TmuxTarget))
+}
+
+// registerReportCallbacks handles the buttons of the end-of-session report.
+// Callback data for both is the session ID.
+func registerReportCallbacks(bot *tele.Bot) {
+	bot.Handle(&tele.InlineButton{Unique: "end_diff"}, func(c tele.Context) error {
+		base, ok := sessionBases.get(c.Data())
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
+		}
+		rev := diffRevision(base)
+		diff, err := sessionDiff(base.CWD, rev)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Session diff failed: session=%s cwd=%s err=%v", base.SessionID, base.CWD, err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "report.diff_failed")})
+		}
+		if strings.TrimSpace(diff) == "" {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "report.no_diff")})
+		}
+		tmp, err := os.CreateTemp("", "tg-cli-*.diff")
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create session diff file: %v", err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "report.diff_failed")})
+		}
+		defer os.Remove(tmp.Name())
+		tmp.WriteString(diff)
+		tmp.Close()
+		if len(rev) > 7 {
+			rev = rev[:7]
+		}
+		project := filepath.Base(base.CWD)
+		doc := &tele.Document{
+			File:     tele.FromDisk(tmp.Name()),
+			FileName: project + ".diff",
+			Caption:  tr(c, "report.diff_caption", project, rev),
+		}
+		if _, err := outbox.send(c.Message().Chat, doc, &tele.SendOptions{ReplyTo: c.Message()}); err != nil {
+			logger.Error(fmt.Sprintf("Failed to send session diff: %v", err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "report.diff_failed")})
+		}
+		logger.Info(fmt.Sprintf("Session diff sent: session=%s rev=%s bytes=%d", base.SessionID, rev, len(diff)))
+		return c.Respond()
+	})
+
+	bot.Handle(&tele.InlineButton{Unique: "end_resume"}, func(c tele.Context) error {
+		sessionID := c.Data()
+		targetPtr, err := messageTarget(c.Message())
+		if err != nil || targetPtr == nil {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.no_target")})
+		}
+		if !checkSessionAlive(injector.FormatTarget(*targetPtr), bot) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
+		}
+		// After /clear Claude Code is still running in the pane; after an
+		// exit the pane is back at the shell.
+		text := "claude --resume " + sessionID
+		if cmd, err := injector.GetPaneCommand(*targetPtr); err == nil && cmd == "claude" {
+			text = "/resume " + sessionID
+		}
+		if err := injector.InjectText(*targetPtr, text); err != nil {
+			logger.Error(fmt.Sprintf("resume inject failed: target=%s session=%s err=%v", injector.FormatTarget(*targetPtr), sessionID, err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.inject_failed")})
+		}
+		logger.Info(fmt.Sprintf("Resume injected: target=%s session=%s cmd=%q", injector.FormatTarget(*targetPtr), sessionID, text))
+		reactAndTrack(bot, c.Message().Chat, c.Message(), injector.FormatTarget(*targetPtr))
+		return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.resuming")})
+	})
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/injector"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/pairing"
+	"github.com/Seraphli/tg-cli/internal/route"
+	tele "gopkg.in/telebot.v3"
+)
+
+// transcriptRootSession returns the first session ID recorded in a
+// transcript. A resumed session's transcript starts with the history of the
+// session it resumed, so this is the same for the whole resume chain.
+func transcriptRootSession(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.SessionID != "" {
+			return entry.SessionID
+		}
+	}
+	return ""
+}
+
+// recordSessionRoot remembers the resume chain of a session and the current
+// location of its pane.
+func recordSessionRoot(sessionID, tmuxTarget, transcriptPath string) {
+	if sessionID != "" {
+		if _, ok := sessionRoots.get(sessionID); !ok {
+			root := transcriptRootSession(transcriptPath)
+			if root == "" {
+				root = sessionID
+			}
+			sessionRoots.set(sessionID, root)
+		}
+	}
+	refreshPaneLocation(tmuxTarget)
+}
+
+// paneLocationTTL is how long a pane location is trusted before tmux is
+// asked again. Hook events refresh it sooner.
+const paneLocationTTL = 30 * time.Second
+
+// paneLocation returns the "session:window.pane" location of a pane, asking
+// tmux only when the last one seen is older than paneLocationTTL.
+func paneLocation(tmuxTarget string) string {
+	target, err := injector.ParseTarget(tmuxTarget)
+	if err != nil {
+		return ""
+	}
+	if loc, seen := paneLocations.get(target.PaneID); loc != "" && time.Since(seen) < paneLocationTTL {
+		return loc
+	}
+	return refreshPaneLocation(tmuxTarget)
+}
+
+// refreshPaneLocation asks tmux for the location of a pane, or returns the
+// last one seen when the pane is gone.
+func refreshPaneLocation(tmuxTarget string) string {
+	target, err := injector.ParseTarget(tmuxTarget)
+	if err != nil {
+		return ""
+	}
+	if loc, err := injector.GetPaneLocation(target); err == nil && loc != "" {
+		paneLocations.set(target.PaneID, loc)
+		return loc
+	}
+	loc, _ := paneLocations.get(target.PaneID)
+	return loc
+}
+
+// sessionIdentity gathers the route keys of the session in a pane.
+func sessionIdentity(tmuxTarget string) route.Identity {
+	target, err := injector.ParseTarget(tmuxTarget)
+	if err != nil {
+		return route.Identity{}
+	}
+	id := route.Identity{PaneID: target.PaneID, Socket: target.Socket, Location: paneLocation(tmuxTarget)}
+	if sid, ok := sessionState.findByTarget(tmuxTarget); ok {
+		if root, ok := sessionRoots.get(sid); ok {
+			id.ClaudeRoot = root
+			id.Alias = sessionAliases.get(root)
+		}
+	}
+	return id
+}
+
+// lookupRouteKey returns the chat a pane's session is bound to and the key
+// that matched. A match on a legacy pane ID key is migrated to the session's
+// stable key on the spot, once a session is known to run in the pane.
+func lookupRouteKey(creds *config.Credentials, tmuxTarget string) (int64, string, bool) {
+	if tmuxTarget == "" || len(creds.RouteMap) == 0 {
+		return 0, "", false
+	}
+	id := sessionIdentity(tmuxTarget)
+	for _, key := range id.Keys() {
+		chatID, ok := creds.RouteMap[key]
+		if !ok {
+			continue
+		}
+		if _, tracked := sessionState.findByTarget(tmuxTarget); tracked && route.IsLegacy(key) {
+			if stable := migratedKey(id); stable != "" {
+				rekeyRoute(creds, key, stable)
+				key = stable
+			}
+		}
+		return chatID, key, true
+	}
+	return 0, "", false
+}
+
+// migratedKey returns the key a legacy pane ID route of id moves to: the
+// pane's location, which means the same pane, or else the session's own key.
+func migratedKey(id route.Identity) string {
+	if id.Location != "" {
+		return route.PaneKey(id.Location, id.Socket)
+	}
+	return id.SessionRouteKey()
+}
+
+// rekeyRoute moves a route from one RouteMap key to another and saves the
+// credentials. An existing binding on the new key is kept.
+func rekeyRoute(creds *config.Credentials, from, to string) {
+	chatID := creds.RouteMap[from]
+	delete(creds.RouteMap, from)
+	if _, ok := creds.RouteMap[to]; !ok {
+		creds.RouteMap[to] = chatID
+	}
+	if err := config.SaveCredentials(*creds); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save migrated route %s → %s: %v", from, to, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Route key migrated: %s → %s (chat=%d)", from, to, chatID))
+}
+
+// migrateRouteKeys runs at startup and drops legacy pane ID routes whose pane
+// is gone. Without a tracked session there is no last known position to keep
+// them under, and tmux reuses pane IDs after a restart. Routes of live panes
+// are migrated by lookupRouteKey once their session sends a hook event, since
+// the pane alone may have been reused by an unrelated one.
+func migrateRouteKeys() {
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		return
+	}
+	changed := false
+	for key, chatID := range creds.RouteMap {
+		if !route.IsLegacy(key) {
+			continue
+		}
+		target, err := injector.ParseTarget(key)
+		if err == nil && injector.SessionExists(target) {
+			continue
+		}
+		delete(creds.RouteMap, key)
+		changed = true
+		logger.Info(fmt.Sprintf("Dropped route of gone pane: key=%s chat=%d", key, chatID))
+	}
+	if !changed {
+		return
+	}
+	if err := config.SaveCredentials(creds); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save routes after dropping gone panes: %v", err))
+	}
+}
+
+// routeKeyTarget returns the live pane a RouteMap key currently refers to,
+// or "" when none does.
+func routeKeyTarget(key string) string {
+	if route.IsLegacy(key) {
+		return key
+	}
+	if loc, socket, ok := route.ParsePaneKey(key); ok {
+		if target, ok := injector.FindPaneByLocation(loc, socket); ok {
+			return injector.FormatTarget(target)
+		}
+		return ""
+	}
+	for _, info := range sessionState.all() {
+		for _, k := range sessionIdentity(info.tmuxTarget).Keys() {
+			if k == key {
+				return info.tmuxTarget
+			}
+		}
+	}
+	return ""
+}
+
+// unbindRouteKeys removes every RouteMap key of the session in a pane and
+// returns the removed keys.
+func unbindRouteKeys(creds *config.Credentials, tmuxTarget string) []string {
+	var removed []string
+	for _, key := range sessionIdentity(tmuxTarget).Keys() {
+		if _, ok := creds.RouteMap[key]; ok {
+			delete(creds.RouteMap, key)
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// aliasPattern is what a session alias may look like.
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,32}$`)
+
+// handleAliasCommand handles /bot_alias [name] — shows or sets the alias of
+// the replied-to session (or the group's only session). Routes bound to an
+// alias follow it to whichever session carries it.
+func handleAliasCommand(c tele.Context) error {
+	if !pairing.IsAllowed(strconv.FormatInt(c.Sender().ID, 10)) {
+		return c.Reply(tr(c, "err.not_paired"))
+	}
+	var tmuxStr string
+	if c.Message().ReplyTo != nil {
+		target, err := messageTarget(c.Message().ReplyTo)
+		if err != nil {
+			return c.Reply(tr(c, "err.no_pane_in_reply"))
+		}
+		tmuxStr = injector.FormatTarget(*target)
+	} else if c.Chat().Type == tele.ChatGroup || c.Chat().Type == tele.ChatSuperGroup {
+		ts, _, err := resolveGroupTarget(c.Message())
+		if err != nil {
+			return c.Reply(tr(c, "err.reply_to_target"))
+		}
+		tmuxStr = ts
+	} else {
+		return c.Reply(tr(c, "err.reply_to_target"))
+	}
+	sid, ok := sessionState.findByTarget(tmuxStr)
+	root, rootOk := sessionRoots.get(sid)
+	if !ok || !rootOk {
+		return c.Reply(tr(c, "alias.no_session"))
+	}
+	name := strings.TrimSpace(c.Message().Payload)
+	if name == "" {
+		if alias := sessionAliases.get(root); alias != "" {
+			return c.Reply(tr(c, "alias.current", alias))
+		}
+		return c.Reply(tr(c, "alias.usage"))
+	}
+	if !aliasPattern.MatchString(name) {
+		return c.Reply(tr(c, "alias.invalid"))
+	}
+	sessionAliases.set(root, name)
+	logger.Info(fmt.Sprintf("Session alias set: session=%s root=%s tmux=%s alias=%s", sid, root, tmuxStr, name))
+	return c.Reply(tr(c, "alias.set", name, notify.FormatPaneID(tmuxStr)))
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/ccsettings"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/injector"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	tele "gopkg.in/telebot.v3"
+)
+
+// maxRuleButtons bounds the rules that get a row of buttons; Telegram allows
+// at most 100 buttons per message.
+const maxRuleButtons = 30
+
+var ruleListIcons = map[string]string{"allow": "✅", "ask": "❓", "deny": "⛔"}
+
+var ruleScopeIcons = map[ccsettings.Scope]string{
+	ccsettings.ScopeUser:    "👤",
+	ccsettings.ScopeProject: "📁",
+	ccsettings.ScopeLocal:   "💻",
+}
+
+// buildRulesMessage lists the permission rules of every settings scope for
+// cwd, with buttons to revoke a rule or move it to another scope.
+// Callback unique: "rule", data: "<index>|rm" or "<index>|<scope>".
+func buildRulesMessage(v *rulesView, lang string) (string, *tele.ReplyMarkup) {
+	lines := []string{
+		i18n.T(lang, "rules.title", notify.CompressPath(v.cwd)),
+		"📟 " + notify.FormatPaneID(v.tmuxTarget),
+	}
+	seen := make(map[string]bool)
+	for _, scope := range ccsettings.Scopes {
+		path := ccsettings.Path(scope, v.cwd)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		lines = append(lines, "", fmt.Sprintf("%s %s · %s", ruleScopeIcons[scope], i18n.T(lang, "rules.scope."+string(scope)), notify.CompressPath(path)))
+		n := 0
+		for i, r := range v.rules {
+			if r.Scope == scope {
+				lines = append(lines, fmt.Sprintf("  %d. %s %s", i+1, ruleListIcons[r.List], r.Rule))
+				n++
+			}
+		}
+		if n == 0 {
+			lines = append(lines, "  "+i18n.T(lang, "rules.empty"))
+		}
+	}
+	if len(v.rules) == 0 {
+		return strings.Join(lines, "\n"), nil
+	}
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for i, r := range v.rules {
+		if i == maxRuleButtons {
+			lines = append(lines, "", i18n.T(lang, "rules.too_many", maxRuleButtons))
+			break
+		}
+		row := tele.Row{markup.Data(fmt.Sprintf("%d 🗑", i+1), "rule", fmt.Sprintf("%d|rm", i))}
+		for _, scope := range ccsettings.Scopes {
+			if ccsettings.Path(scope, v.cwd) == ccsettings.Path(r.Scope, v.cwd) {
+				continue
+			}
+			row = append(row, markup.Data(fmt.Sprintf("%d → %s", i+1, ruleScopeIcons[scope]), "rule", fmt.Sprintf("%d|%s", i, scope)))
+		}
+		rows = append(rows, row)
+	}
+	markup.Inline(rows...)
+	return strings.Join(lines, "\n"), markup
+}
+
+// handleRulesCommand handles /bot_rules — lists the permission rules that
+// apply to the session's working directory.
+func handleRulesCommand(c tele.Context, target injector.TmuxTarget) error {
+	cwd := targetCWD(target)
+	if cwd == "" {
+		return c.Reply(tr(c, "git.no_cwd"))
+	}
+	rules, err := ccsettings.Rules(cwd)
+	if err != nil {
+		return c.Reply(tr(c, "rules.read_failed", err))
+	}
+	v := &rulesView{cwd: cwd, tmuxTarget: injector.FormatTarget(target), rules: rules}
+	text, markup := buildRulesMessage(v, chatLang(c.Chat().ID))
+	sent, err := outbox.send(c.Chat(), text, &tele.SendOptions{ReplyTo: c.Message(), ReplyMarkup: markup})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send rules: %v", err))
+		return nil
+	}
+	msgTargets.record(c.Chat().ID, sent.ID, v.tmuxTarget)
+	rulesViews.set(c.Chat().ID, sent.ID, v)
+	logger.Info(fmt.Sprintf("Rules listed: cwd=%s rules=%d msg_id=%d", cwd, len(rules), sent.ID))
+	return nil
+}
+
+// registerRulesCallbacks handles the revoke and move buttons of /bot_rules.
+func registerRulesCallbacks(bot *tele.Bot) {
+	bot.Handle(&tele.InlineButton{Unique: "rule"}, func(c tele.Context) error {
+		v, ok := rulesViews.get(c.Chat().ID, c.Message().ID)
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
+		}
+		idxStr, action, _ := strings.Cut(c.Data(), "|")
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil || idx < 0 || idx >= len(v.rules) {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+		}
+		r := v.rules[idx]
+		var toast string
+		if action == "rm" {
+			err = ccsettings.Remove(v.cwd, r)
+			toast = tr(c, "rules.removed", r.Rule)
+		} else {
+			to := ccsettings.Scope(action)
+			err = ccsettings.Move(v.cwd, r, to)
+			toast = tr(c, "rules.moved", r.Rule, tr(c, "rules.scope."+action))
+		}
+		if errors.Is(err, ccsettings.ErrNotFound) {
+			toast = tr(c, "rules.changed")
+		} else if err != nil {
+			logger.Error(fmt.Sprintf("Rule edit failed: cwd=%s rule=%q action=%s err=%v", v.cwd, r.Rule, action, err))
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "err.generic", err)})
+		} else {
+			logger.Info(fmt.Sprintf("Rule edited: cwd=%s scope=%s list=%s rule=%q action=%s", v.cwd, r.Scope, r.List, r.Rule, action))
+		}
+		rules, err := ccsettings.Rules(v.cwd)
+		if err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "rules.read_failed", err)})
+		}
+		nv := &rulesView{cwd: v.cwd, tmuxTarget: v.tmuxTarget, rules: rules}
+		rulesViews.set(c.Chat().ID, c.Message().ID, nv)
+		text, markup := buildRulesMessage(nv, ctxLang(c))
+		if _, err := outbox.edit(c.Message(), text, markup); err != nil {
+			logger.Debug(fmt.Sprintf("rules edit error: %v", err))
+		}
+		return c.Respond(&tele.CallbackResponse{Text: toast})
+	})
+}
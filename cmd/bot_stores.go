@@ -3,8 +3,17 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/Seraphli/tg-cli/internal/ccsettings"
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
 	"github.com/Seraphli/tg-cli/internal/logger"
 	"github.com/Seraphli/tg-cli/internal/notify"
 	tele "gopkg.in/telebot.v3"
@@ -50,46 +59,74 @@ var ccBuiltinCommands = map[string]string{
 
 type pageCacheStore struct {
 	mu       sync.RWMutex
-	entries  map[int]*pageEntry
-	sessions map[string][]int // sessionID → []messageID
+	entries  map[msgKey]*pageEntry
+	sessions map[string][]msgKey // sessionID → messages
 }
 
 type pageEntry struct {
 	chunks     []string
 	event      string
+	subject    string
+	failed     bool
 	project    string
 	cwd        string
 	tmuxTarget string
 	permRows   []tele.Row // non-nil for permission messages
+	raw        bool       // chunks are complete message texts (e.g. /bot_git output)
 	chatID     int64
 }
 
 var pages = &pageCacheStore{
-	entries:  make(map[int]*pageEntry),
-	sessions: make(map[string][]int),
+	entries:  make(map[msgKey]*pageEntry),
+	sessions: make(map[string][]msgKey),
 }
 
-func (pc *pageCacheStore) store(msgID int, sessionID string, entry *pageEntry) {
+func (pc *pageCacheStore) store(k msgKey, sessionID string, entry *pageEntry) {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
-	pc.entries[msgID] = entry
+	pc.entries[k] = entry
 	if sessionID != "" {
-		pc.sessions[sessionID] = append(pc.sessions[sessionID], msgID)
+		pc.sessions[sessionID] = append(pc.sessions[sessionID], k)
 	}
 }
 
-func (pc *pageCacheStore) get(msgID int) (*pageEntry, bool) {
+// storeFor keeps a page entry that belongs to no session for ttl.
+func (pc *pageCacheStore) storeFor(k msgKey, ttl time.Duration, entry *pageEntry) {
+	pc.store(k, "", entry)
+	time.AfterFunc(ttl, func() {
+		pc.mu.Lock()
+		defer pc.mu.Unlock()
+		if pc.entries[k] == entry {
+			delete(pc.entries, k)
+		}
+	})
+}
+
+func (pc *pageCacheStore) get(k msgKey) (*pageEntry, bool) {
 	pc.mu.RLock()
 	defer pc.mu.RUnlock()
-	e, ok := pc.entries[msgID]
+	e, ok := pc.entries[k]
 	return e, ok
 }
 
+// chatOf returns the chat of a paginated message by ID alone, for API
+// callers that don't pass the chat.
+func (pc *pageCacheStore) chatOf(msgID int) (int64, bool) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	for k := range pc.entries {
+		if k.msgID == msgID {
+			return k.chatID, true
+		}
+	}
+	return 0, false
+}
+
 func (pc *pageCacheStore) cleanupSession(sessionID string) {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
-	for _, msgID := range pc.sessions[sessionID] {
-		delete(pc.entries, msgID)
+	for _, k := range pc.sessions[sessionID] {
+		delete(pc.entries, k)
 	}
 	delete(pc.sessions, sessionID)
 }
@@ -100,88 +137,149 @@ type permDecision struct {
 	UpdatedPermissions json.RawMessage `json:"updatedPermissions,omitempty"`
 }
 
+// msgKey identifies a Telegram message. Message IDs are only unique within
+// a chat, and fan-out copies of a request live in several chats.
+type msgKey struct {
+	chatID int64
+	msgID  int
+}
+
+func keyOf(m *tele.Message) msgKey {
+	return msgKey{m.Chat.ID, m.ID}
+}
+
+// sortMsgKeys orders keys by chat, then message.
+func sortMsgKeys(keys []msgKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].chatID != keys[j].chatID {
+			return keys[i].chatID < keys[j].chatID
+		}
+		return keys[i].msgID < keys[j].msgID
+	})
+}
+
 type pendingPermStore struct {
 	mu          sync.RWMutex
-	targets     map[int]string
-	suggestions map[int]json.RawMessage
-	msgTexts    map[int]string
-	chatIDs     map[int]int64
-	uuids       map[int]string
+	targets     map[msgKey]string
+	suggestions map[msgKey]json.RawMessage
+	msgTexts    map[msgKey]string
+	uuids       map[msgKey]string
 }
 
 var pendingPerms = &pendingPermStore{
-	targets:     make(map[int]string),
-	suggestions: make(map[int]json.RawMessage),
-	msgTexts:    make(map[int]string),
-	chatIDs:     make(map[int]int64),
-	uuids:       make(map[int]string),
+	targets:     make(map[msgKey]string),
+	suggestions: make(map[msgKey]json.RawMessage),
+	msgTexts:    make(map[msgKey]string),
+	uuids:       make(map[msgKey]string),
 }
 
-func (ps *pendingPermStore) create(msgID int, tmuxTarget string, suggestionsJSON json.RawMessage, msgText string, chatID int64, uuid string) {
+func (ps *pendingPermStore) create(chatID int64, msgID int, tmuxTarget string, suggestionsJSON json.RawMessage, msgText string, uuid string) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
-	ps.targets[msgID] = tmuxTarget
-	ps.suggestions[msgID] = suggestionsJSON
-	ps.msgTexts[msgID] = msgText
-	ps.chatIDs[msgID] = chatID
-	ps.uuids[msgID] = uuid
+	k := msgKey{chatID, msgID}
+	ps.targets[k] = tmuxTarget
+	ps.suggestions[k] = suggestionsJSON
+	ps.msgTexts[k] = msgText
+	ps.uuids[k] = uuid
 }
 
-func (ps *pendingPermStore) resolve(msgID int, d permDecision) bool {
+func (ps *pendingPermStore) resolve(chatID int64, msgID int, d permDecision) bool {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
-	_, ok := ps.targets[msgID]
+	k := msgKey{chatID, msgID}
+	_, ok := ps.targets[k]
 	if !ok {
 		return false
 	}
-	delete(ps.targets, msgID)
-	delete(ps.suggestions, msgID)
-	delete(ps.msgTexts, msgID)
-	delete(ps.chatIDs, msgID)
-	delete(ps.uuids, msgID)
+	ps.deleteLocked(k)
 	return true
 }
 
-func (ps *pendingPermStore) getUUID(msgID int) (string, bool) {
+func (ps *pendingPermStore) getUUID(chatID int64, msgID int) (string, bool) {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
-	uuid, ok := ps.uuids[msgID]
+	uuid, ok := ps.uuids[msgKey{chatID, msgID}]
 	return uuid, ok
 }
 
-func (ps *pendingPermStore) getTarget(msgID int) (string, bool) {
+func (ps *pendingPermStore) getTarget(chatID int64, msgID int) (string, bool) {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
-	t, ok := ps.targets[msgID]
+	t, ok := ps.targets[msgKey{chatID, msgID}]
 	return t, ok
 }
 
-func (ps *pendingPermStore) getSuggestions(msgID int) json.RawMessage {
+func (ps *pendingPermStore) getSuggestions(chatID int64, msgID int) json.RawMessage {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
-	return ps.suggestions[msgID]
+	return ps.suggestions[msgKey{chatID, msgID}]
 }
 
-func (ps *pendingPermStore) getMsgText(msgID int) string {
+func (ps *pendingPermStore) getMsgText(chatID int64, msgID int) string {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
-	return ps.msgTexts[msgID]
+	return ps.msgTexts[msgKey{chatID, msgID}]
 }
 
-func (ps *pendingPermStore) getChatID(msgID int) int64 {
+// chatOf returns the chat of the unanswered permission request with message
+// ID msgID, for API callers that only know the message ID.
+func (ps *pendingPermStore) chatOf(msgID int) (int64, bool) {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
-	return ps.chatIDs[msgID]
+	for k := range ps.targets {
+		if k.msgID == msgID {
+			return k.chatID, true
+		}
+	}
+	return 0, false
 }
 
-func (ps *pendingPermStore) cleanup(msgID int) {
+// countByTarget returns the number of unanswered permission requests for a
+// pane, counting fan-out copies of a request once.
+func (ps *pendingPermStore) countByTarget(tmuxTarget string) int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	normalized := notify.FormatPaneID(tmuxTarget)
+	seen := make(map[string]bool)
+	n := 0
+	for k, t := range ps.targets {
+		if notify.FormatPaneID(t) != normalized {
+			continue
+		}
+		if uuid := ps.uuids[k]; uuid != "" {
+			if seen[uuid] {
+				continue
+			}
+			seen[uuid] = true
+		}
+		n++
+	}
+	return n
+}
+
+// keys returns the messages of all unanswered permission requests.
+func (ps *pendingPermStore) keys() []msgKey {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	keys := make([]msgKey, 0, len(ps.targets))
+	for k := range ps.targets {
+		keys = append(keys, k)
+	}
+	sortMsgKeys(keys)
+	return keys
+}
+
+func (ps *pendingPermStore) cleanup(chatID int64, msgID int) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
-	delete(ps.targets, msgID)
-	delete(ps.suggestions, msgID)
-	delete(ps.msgTexts, msgID)
-	delete(ps.chatIDs, msgID)
-	delete(ps.uuids, msgID)
+	ps.deleteLocked(msgKey{chatID, msgID})
+}
+
+func (ps *pendingPermStore) deleteLocked(k msgKey) {
+	delete(ps.targets, k)
+	delete(ps.suggestions, k)
+	delete(ps.msgTexts, k)
+	delete(ps.uuids, k)
 }
 
 type questionMeta struct {
@@ -192,6 +290,7 @@ type questionMeta struct {
 	multiSelect     bool
 	selectedOptions map[int]bool
 	selectedOption  int
+	customAnswer    string // typed via the ✏️ Other button
 }
 
 type toolNotifyEntry struct {
@@ -206,89 +305,148 @@ type toolNotifyEntry struct {
 
 type toolNotifyStore struct {
 	mu      sync.RWMutex
-	entries map[int]*toolNotifyEntry
+	entries map[msgKey]*toolNotifyEntry
 }
 
 var toolNotifs = &toolNotifyStore{
-	entries: make(map[int]*toolNotifyEntry),
+	entries: make(map[msgKey]*toolNotifyEntry),
 }
 
-func (ts *toolNotifyStore) store(msgID int, entry *toolNotifyEntry) {
+func (ts *toolNotifyStore) store(chatID int64, msgID int, entry *toolNotifyEntry) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
-	ts.entries[msgID] = entry
+	ts.entries[msgKey{chatID, msgID}] = entry
 }
 
-func (ts *toolNotifyStore) get(msgID int) (*toolNotifyEntry, bool) {
+func (ts *toolNotifyStore) get(chatID int64, msgID int) (*toolNotifyEntry, bool) {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
-	e, ok := ts.entries[msgID]
+	e, ok := ts.entries[msgKey{chatID, msgID}]
 	return e, ok
 }
 
-func (ts *toolNotifyStore) markResolved(msgID int) {
+func (ts *toolNotifyStore) markResolved(chatID int64, msgID int) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
-	if e, ok := ts.entries[msgID]; ok {
+	if e, ok := ts.entries[msgKey{chatID, msgID}]; ok {
 		e.resolved = true
 	}
 }
 
-func (ts *toolNotifyStore) findByTmuxTarget(tmuxTarget string) (int, *toolNotifyEntry, bool) {
+// chatOf returns the chat of the tool notification with message ID msgID,
+// preferring unanswered ones, for API callers that only know the message ID.
+func (ts *toolNotifyStore) chatOf(msgID int) (int64, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	chatID, found := int64(0), false
+	for k, e := range ts.entries {
+		if k.msgID != msgID {
+			continue
+		}
+		if !e.resolved {
+			return k.chatID, true
+		}
+		chatID, found = k.chatID, true
+	}
+	return chatID, found
+}
+
+// findByTmuxTarget returns the unanswered question of a pane shown in
+// chatID. Fan-out copies of a question share the pane, so the chat decides
+// which copy is being answered; chatID 0 matches any chat.
+func (ts *toolNotifyStore) findByTmuxTarget(chatID int64, tmuxTarget string) (msgKey, *toolNotifyEntry, bool) {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
 	normalized := notify.FormatPaneID(tmuxTarget)
-	for msgID, e := range ts.entries {
+	for k, e := range ts.entries {
+		if chatID != 0 && k.chatID != chatID {
+			continue
+		}
 		if notify.FormatPaneID(e.tmuxTarget) == normalized && e.toolName == "AskUserQuestion" && !e.resolved {
-			return msgID, e, true
+			return k, e, true
 		}
 	}
-	return 0, nil, false
+	return msgKey{}, nil, false
+}
+
+// countPendingByTarget returns the number of unanswered questions for a
+// pane, counting fan-out copies of a question once.
+func (ts *toolNotifyStore) countPendingByTarget(tmuxTarget string) int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	normalized := notify.FormatPaneID(tmuxTarget)
+	seen := make(map[string]bool)
+	n := 0
+	for _, e := range ts.entries {
+		if notify.FormatPaneID(e.tmuxTarget) != normalized || e.toolName != "AskUserQuestion" || e.resolved {
+			continue
+		}
+		if e.pendingUUID != "" {
+			if seen[e.pendingUUID] {
+				continue
+			}
+			seen[e.pendingUUID] = true
+		}
+		n++
+	}
+	return n
+}
+
+// unresolved returns the messages of all unanswered questions.
+func (ts *toolNotifyStore) unresolved() []msgKey {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	var keys []msgKey
+	for k, e := range ts.entries {
+		if e.toolName == "AskUserQuestion" && !e.resolved {
+			keys = append(keys, k)
+		}
+	}
+	sortMsgKeys(keys)
+	return keys
 }
 
 type pendingFileStore struct {
 	mu      sync.RWMutex
-	entries map[int]string
+	entries map[msgKey]string
 }
 
 var pendingFiles = &pendingFileStore{
-	entries: make(map[int]string),
+	entries: make(map[msgKey]string),
 }
 
-func (pfs *pendingFileStore) store(msgID int, uuid string) {
+func (pfs *pendingFileStore) store(chatID int64, msgID int, uuid string) {
 	pfs.mu.Lock()
 	defer pfs.mu.Unlock()
-	pfs.entries[msgID] = uuid
+	pfs.entries[msgKey{chatID, msgID}] = uuid
 }
 
-func (pfs *pendingFileStore) get(msgID int) (string, bool) {
+func (pfs *pendingFileStore) get(chatID int64, msgID int) (string, bool) {
 	pfs.mu.RLock()
 	defer pfs.mu.RUnlock()
-	uuid, ok := pfs.entries[msgID]
+	uuid, ok := pfs.entries[msgKey{chatID, msgID}]
 	return uuid, ok
 }
 
-func (pfs *pendingFileStore) delete(msgID int) {
-	pfs.mu.Lock()
-	defer pfs.mu.Unlock()
-	delete(pfs.entries, msgID)
-}
-
-func (s *pendingFileStore) findByUUID(uuid string) (int, bool) {
+// findByUUID returns the messages sent for a pending request: its own and
+// any fan-out copies.
+func (s *pendingFileStore) findByUUID(uuid string) []msgKey {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	for msgID, u := range s.entries {
+	var keys []msgKey
+	for k, u := range s.entries {
 		if u == uuid {
-			return msgID, true
+			keys = append(keys, k)
 		}
 	}
-	return 0, false
+	sortMsgKeys(keys)
+	return keys
 }
 
-func (s *pendingFileStore) remove(msgID int) {
+func (s *pendingFileStore) remove(chatID int64, msgID int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.entries, msgID)
+	delete(s.entries, msgKey{chatID, msgID})
 }
 
 type sessionCountStore struct {
@@ -322,6 +480,8 @@ func (s *sessionCountStore) cleanup(sessionID string) {
 type sessionInfo struct {
 	tmuxTarget string
 	cwd        string
+	route      string    // TG_CLI_ROUTE of the session, for routing rules
+	lastEvent  time.Time // last hook event seen for the session
 }
 
 // sessionStateStore tracks active CC sessions and their associated info.
@@ -332,10 +492,10 @@ type sessionStateStore struct {
 
 var sessionState = &sessionStateStore{sessions: make(map[string]sessionInfo)}
 
-func (s *sessionStateStore) add(sessionID, tmuxTarget, cwd string) {
+func (s *sessionStateStore) add(sessionID, tmuxTarget, cwd, route string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.sessions[sessionID] = sessionInfo{tmuxTarget: tmuxTarget, cwd: cwd}
+	s.sessions[sessionID] = sessionInfo{tmuxTarget: tmuxTarget, cwd: cwd, route: route, lastEvent: time.Now()}
 }
 
 func (s *sessionStateStore) remove(sessionID string) {
@@ -344,7 +504,6 @@ func (s *sessionStateStore) remove(sessionID string) {
 	delete(s.sessions, sessionID)
 }
 
-
 func (s *sessionStateStore) all() map[string]sessionInfo {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -423,10 +582,862 @@ func (rt *reactionTrackerStore) clearAndRemove(bot *tele.Bot, tmuxTarget string)
 		logger.Debug(fmt.Sprintf("Clearing %d reactions for target %s", len(rEntries), tmuxTarget))
 	}
 	for _, e := range rEntries {
-		bot.Raw("setMessageReaction", map[string]interface{}{
-			"chat_id":    e.chatID,
-			"message_id": e.msgID,
-			"reaction":   []interface{}{},
+		e := e
+		outbox.do(e.chatID, func() error {
+			_, err := bot.Raw("setMessageReaction", map[string]interface{}{
+				"chat_id":    e.chatID,
+				"message_id": e.msgID,
+				"reaction":   []interface{}{},
+			})
+			return err
 		})
 	}
 }
+
+// chatLangStore remembers the Telegram language_code last seen in each chat so
+// notifications sent from hooks can follow it.
+type chatLangStore struct {
+	mu       sync.Mutex
+	observed map[int64]string
+}
+
+var chatLangs = &chatLangStore{observed: make(map[int64]string)}
+
+func (s *chatLangStore) observe(chatID int64, code string) {
+	lang := i18n.Normalize(code)
+	if lang == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observed[chatID] = lang
+}
+
+func (s *chatLangStore) get(chatID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.observed[chatID]
+}
+
+// msgTargetCap bounds the message → pane map; the oldest entries go first.
+const msgTargetCap = 2000
+
+type msgTargetEntry struct {
+	Key    string `json:"key"`
+	Target string `json:"target"`
+}
+
+// msgTargetStore maps sent messages to the tmux target they belong to, so
+// replies route correctly even when a template drops the 📟 line. It is
+// persisted to <config-dir>/msg_targets.json to survive restarts.
+type msgTargetStore struct {
+	mu      sync.Mutex
+	targets map[string]string
+	order   []string
+}
+
+var msgTargets = &msgTargetStore{targets: make(map[string]string)}
+
+func msgTargetKey(chatID int64, msgID int) string {
+	return fmt.Sprintf("%d:%d", chatID, msgID)
+}
+
+func msgTargetsPath() string {
+	return filepath.Join(config.GetConfigDir(), "msg_targets.json")
+}
+
+func (s *msgTargetStore) load() {
+	data, err := os.ReadFile(msgTargetsPath())
+	if err != nil {
+		return
+	}
+	var entries []msgTargetEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse msg_targets.json: %v", err))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		if _, ok := s.targets[e.Key]; !ok {
+			s.order = append(s.order, e.Key)
+		}
+		s.targets[e.Key] = e.Target
+	}
+}
+
+func (s *msgTargetStore) record(chatID int64, msgID int, tmuxTarget string) {
+	if tmuxTarget == "" || msgID == 0 {
+		return
+	}
+	key := msgTargetKey(chatID, msgID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.targets[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.targets[key] = tmuxTarget
+	for len(s.order) > msgTargetCap {
+		delete(s.targets, s.order[0])
+		s.order = s.order[1:]
+	}
+	entries := make([]msgTargetEntry, 0, len(s.order))
+	for _, k := range s.order {
+		entries = append(entries, msgTargetEntry{Key: k, Target: s.targets[k]})
+	}
+	data, _ := json.Marshal(entries)
+	if err := os.WriteFile(msgTargetsPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save msg_targets.json: %v", err))
+	}
+}
+
+func (s *msgTargetStore) get(chatID int64, msgID int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.targets[msgTargetKey(chatID, msgID)]
+	return t, ok
+}
+
+// todoMsgStore tracks the TodoWrite checklist message of each session per
+// chat, so later TodoWrite calls edit it in place.
+type todoMsgStore struct {
+	mu   sync.Mutex
+	msgs map[string]map[int64]int // sessionID → chatID → messageID
+}
+
+var todoMsgs = &todoMsgStore{msgs: make(map[string]map[int64]int)}
+
+func (s *todoMsgStore) get(sessionID string, chatID int64) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.msgs[sessionID][chatID]
+	return id, ok
+}
+
+func (s *todoMsgStore) set(sessionID string, chatID int64, msgID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.msgs[sessionID] == nil {
+		s.msgs[sessionID] = make(map[int64]int)
+	}
+	s.msgs[sessionID][chatID] = msgID
+}
+
+func (s *todoMsgStore) cleanupSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.msgs, sessionID)
+}
+
+// contextAlertStore remembers the highest context threshold each session has
+// been alerted for, and which messages are context alerts.
+type contextAlertStore struct {
+	mu     sync.Mutex
+	levels map[string]int    // sessionID → highest threshold crossed
+	msgs   map[string]string // chat:msg key → sessionID
+}
+
+var contextAlerts = &contextAlertStore{
+	levels: make(map[string]int),
+	msgs:   make(map[string]string),
+}
+
+// update records the threshold level a session is at and reports whether it
+// just crossed a higher one. Dropping below a threshold (e.g. after
+// compaction) re-arms it.
+func (s *contextAlertStore) update(sessionID string, level int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.levels[sessionID]
+	s.levels[sessionID] = level
+	return level > prev
+}
+
+func (s *contextAlertStore) markMsg(chatID int64, msgID int, sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs[msgTargetKey(chatID, msgID)] = sessionID
+}
+
+func (s *contextAlertStore) isAlert(chatID int64, msgID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.msgs[msgTargetKey(chatID, msgID)]
+	return ok
+}
+
+func (s *contextAlertStore) cleanupSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.levels, sessionID)
+	for k, sid := range s.msgs {
+		if sid == sessionID {
+			delete(s.msgs, k)
+		}
+	}
+}
+
+// overviewStore holds the pinned overview message of each chat, persisted to
+// <config-dir>/overview.json so restarts keep editing the same message.
+type overviewStore struct {
+	mu       sync.Mutex
+	msgs     map[int64]int
+	lastText map[int64]string
+}
+
+var overviews = &overviewStore{msgs: make(map[int64]int), lastText: make(map[int64]string)}
+
+func overviewPath() string {
+	return filepath.Join(config.GetConfigDir(), "overview.json")
+}
+
+func (s *overviewStore) load() {
+	data, err := os.ReadFile(overviewPath())
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := json.Unmarshal(data, &s.msgs); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse overview.json: %v", err))
+	}
+}
+
+func (s *overviewStore) chats() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int64
+	for id := range s.msgs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *overviewStore) get(chatID int64) (int, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.msgs[chatID]
+	return id, s.lastText[chatID], ok
+}
+
+func (s *overviewStore) set(chatID int64, msgID int, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.msgs[chatID] != msgID
+	s.msgs[chatID] = msgID
+	s.lastText[chatID] = text
+	if changed {
+		s.saveLocked()
+	}
+}
+
+func (s *overviewStore) remove(chatID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.msgs, chatID)
+	delete(s.lastText, chatID)
+	s.saveLocked()
+}
+
+func (s *overviewStore) saveLocked() {
+	data, _ := json.Marshal(s.msgs)
+	if err := os.WriteFile(overviewPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save overview.json: %v", err))
+	}
+}
+
+// sessionBaseCap bounds the number of remembered session start commits.
+const sessionBaseCap = 200
+
+// sessionBase is the git commit a session's working directory was at when
+// the session started.
+type sessionBase struct {
+	SessionID string `json:"session_id"`
+	CWD       string `json:"cwd"`
+	Commit    string `json:"commit"`
+}
+
+// sessionBaseStore keeps the start commit of recent sessions for the
+// end-of-session report and its diff button. It is persisted to
+// <config-dir>/session_bases.json to survive restarts.
+type sessionBaseStore struct {
+	mu    sync.Mutex
+	bases map[string]sessionBase
+	order []string
+}
+
+var sessionBases = &sessionBaseStore{bases: make(map[string]sessionBase)}
+
+func sessionBasesPath() string {
+	return filepath.Join(config.GetConfigDir(), "session_bases.json")
+}
+
+func (s *sessionBaseStore) load() {
+	data, err := os.ReadFile(sessionBasesPath())
+	if err != nil {
+		return
+	}
+	var entries []sessionBase
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse session_bases.json: %v", err))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		if _, ok := s.bases[e.SessionID]; !ok {
+			s.order = append(s.order, e.SessionID)
+		}
+		s.bases[e.SessionID] = e
+	}
+}
+
+func (s *sessionBaseStore) has(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.bases[sessionID]
+	return ok
+}
+
+func (s *sessionBaseStore) get(sessionID string) (sessionBase, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.bases[sessionID]
+	return b, ok
+}
+
+func (s *sessionBaseStore) record(b sessionBase) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.bases[b.SessionID]; !ok {
+		s.order = append(s.order, b.SessionID)
+	}
+	s.bases[b.SessionID] = b
+	for len(s.order) > sessionBaseCap {
+		delete(s.bases, s.order[0])
+		s.order = s.order[1:]
+	}
+	entries := make([]sessionBase, 0, len(s.order))
+	for _, id := range s.order {
+		entries = append(entries, s.bases[id])
+	}
+	data, _ := json.Marshal(entries)
+	if err := os.WriteFile(sessionBasesPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save session_bases.json: %v", err))
+	}
+}
+
+// rulesView is the snapshot of permission rules shown in a /bot_rules message;
+// its buttons refer to rules by index.
+type rulesView struct {
+	cwd        string
+	tmuxTarget string
+	rules      []ccsettings.Rule
+}
+
+type rulesViewStore struct {
+	mu    sync.Mutex
+	views map[string]*rulesView // chat:msg key → view
+}
+
+var rulesViews = &rulesViewStore{views: make(map[string]*rulesView)}
+
+func (s *rulesViewStore) set(chatID int64, msgID int, v *rulesView) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.views[msgTargetKey(chatID, msgID)] = v
+}
+
+func (s *rulesViewStore) get(chatID int64, msgID int) (*rulesView, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.views[msgTargetKey(chatID, msgID)]
+	return v, ok
+}
+
+// pendingView is the snapshot shown in a /bot_pending message: the
+// permission requests of each listed session, in list order. Batch buttons
+// only act on these, never on requests that arrived after the list was sent.
+type pendingView struct {
+	targets []string   // tmux target per listed session
+	perms   [][]msgKey // permission messages per listed session
+}
+
+type pendingViewStore struct {
+	mu    sync.Mutex
+	views map[string]*pendingView // chat:msg key → view
+}
+
+var pendingViews = &pendingViewStore{views: make(map[string]*pendingView)}
+
+func (s *pendingViewStore) set(chatID int64, msgID int, v *pendingView) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.views[msgTargetKey(chatID, msgID)] = v
+}
+
+func (s *pendingViewStore) get(chatID int64, msgID int) (*pendingView, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.views[msgTargetKey(chatID, msgID)]
+	return v, ok
+}
+
+// permEditPromptStore maps the "reply with the new value" prompts of the
+// permission ✏️ Edit button to the permission message they belong to.
+type permEditPromptStore struct {
+	mu      sync.Mutex
+	prompts map[string]int // chat:prompt msg key → permission msg ID
+}
+
+var permEditPrompts = &permEditPromptStore{prompts: make(map[string]int)}
+
+func (s *permEditPromptStore) set(chatID int64, promptID, permMsgID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts[msgTargetKey(chatID, promptID)] = permMsgID
+}
+
+func (s *permEditPromptStore) get(chatID int64, promptID int) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.prompts[msgTargetKey(chatID, promptID)]
+	return id, ok
+}
+
+// questionInputTTL is how long a question's ✏️ Other button waits for the
+// typed answer.
+const questionInputTTL = 5 * time.Minute
+
+// questionInput is a question waiting for a typed "Other" answer.
+type questionInput struct {
+	msgID    int
+	promptID int // the bot's "type your answer" message
+	qIdx     int
+	expires  time.Time
+}
+
+// questionInputStore tracks, per chat and user, the question whose next text
+// or voice message is taken as its "Other" answer.
+type questionInputStore struct {
+	mu     sync.Mutex
+	inputs map[string]questionInput // chat:user key
+}
+
+var questionInputs = &questionInputStore{inputs: make(map[string]questionInput)}
+
+func (s *questionInputStore) start(chatID, userID int64, msgID, qIdx int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs[fmt.Sprintf("%d:%d", chatID, userID)] = questionInput{msgID: msgID, qIdx: qIdx, expires: time.Now().Add(questionInputTTL)}
+}
+
+// setPrompt records the prompt sent for the user's waiting question, so a
+// reply to it counts as the answer too.
+func (s *questionInputStore) setPrompt(chatID, userID int64, msgID, promptID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%d:%d", chatID, userID)
+	if in, ok := s.inputs[key]; ok && in.msgID == msgID {
+		in.promptID = promptID
+		s.inputs[key] = in
+	}
+}
+
+// take returns and clears the user's waiting question, if it hasn't expired.
+// A message replying to anything other than the question or its prompt
+// (replyTo, 0 for none) is not the answer and leaves the question waiting.
+func (s *questionInputStore) take(chatID, userID int64, replyTo int) (questionInput, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%d:%d", chatID, userID)
+	in, ok := s.inputs[key]
+	if ok && replyTo != 0 && replyTo != in.msgID && replyTo != in.promptID {
+		return questionInput{}, false
+	}
+	delete(s.inputs, key)
+	if !ok || time.Now().After(in.expires) {
+		return questionInput{}, false
+	}
+	return in, true
+}
+
+// sessionTopic is the forum topic a session's messages go to.
+type sessionTopic struct {
+	ChatID     int64  `json:"chat_id"`
+	SessionID  string `json:"session_id"`
+	ThreadID   int    `json:"thread_id"`
+	TmuxTarget string `json:"tmux_target"`
+}
+
+// sessionTopicStore maps sessions to their forum topics and back. Topics of
+// ended sessions are dropped. It is persisted to <config-dir>/topics.json so
+// messages in a topic still reach their session after a restart.
+type sessionTopicStore struct {
+	mu     sync.Mutex
+	topics map[string]sessionTopic // chat:session key
+	forums map[int64]forumCheck    // chat → is a forum, cached for forumCheckTTL
+}
+
+// forumCheckTTL is how long a chat's forum flag is trusted, so a group that
+// enables topics later is picked up.
+const forumCheckTTL = 10 * time.Minute
+
+type forumCheck struct {
+	forum   bool
+	checked time.Time
+}
+
+var sessionTopics = &sessionTopicStore{
+	topics: make(map[string]sessionTopic),
+	forums: make(map[int64]forumCheck),
+}
+
+func sessionTopicsPath() string {
+	return filepath.Join(config.GetConfigDir(), "topics.json")
+}
+
+func sessionTopicKey(chatID int64, sessionID string) string {
+	return fmt.Sprintf("%d:%s", chatID, sessionID)
+}
+
+func (s *sessionTopicStore) load() {
+	data, err := os.ReadFile(sessionTopicsPath())
+	if err != nil {
+		return
+	}
+	var entries []sessionTopic
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse topics.json: %v", err))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		s.topics[sessionTopicKey(e.ChatID, e.SessionID)] = e
+	}
+}
+
+// save writes the topics to disk. Callers hold s.mu.
+func (s *sessionTopicStore) save() {
+	entries := make([]sessionTopic, 0, len(s.topics))
+	for _, t := range s.topics {
+		entries = append(entries, t)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ThreadID < entries[j].ThreadID })
+	data, _ := json.Marshal(entries)
+	if err := os.WriteFile(sessionTopicsPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save topics.json: %v", err))
+	}
+}
+
+// thread returns the topic of a session in chatID, or 0 when it has none.
+func (s *sessionTopicStore) thread(chatID int64, sessionID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.topics[sessionTopicKey(chatID, sessionID)].ThreadID
+}
+
+// byThread returns the session that owns a topic.
+func (s *sessionTopicStore) byThread(chatID int64, threadID int) (sessionTopic, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.topics {
+		if t.ChatID == chatID && t.ThreadID == threadID {
+			return t, true
+		}
+	}
+	return sessionTopic{}, false
+}
+
+func (s *sessionTopicStore) set(t sessionTopic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.topics[sessionTopicKey(t.ChatID, t.SessionID)] = t
+	s.save()
+}
+
+func (s *sessionTopicStore) remove(chatID int64, sessionID string) (sessionTopic, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := sessionTopicKey(chatID, sessionID)
+	t, ok := s.topics[key]
+	if ok {
+		delete(s.topics, key)
+		s.save()
+	}
+	return t, ok
+}
+
+// isForum reports whether chatID is a forum supergroup, asking Telegram
+// again once the cached answer is older than forumCheckTTL.
+func (s *sessionTopicStore) isForum(bot *tele.Bot, chatID int64) bool {
+	s.mu.Lock()
+	check, ok := s.forums[chatID]
+	s.mu.Unlock()
+	if ok && time.Since(check.checked) < forumCheckTTL {
+		return check.forum
+	}
+	// tele.Chat has no is_forum field, so read it from the raw getChat result.
+	var data []byte
+	err := outbox.do(chatID, func() error {
+		var err error
+		data, err = bot.Raw("getChat", map[string]string{"chat_id": strconv.FormatInt(chatID, 10)})
+		return err
+	})
+	if err != nil {
+		return false
+	}
+	var resp struct {
+		Result struct {
+			IsForum bool `json:"is_forum"`
+		} `json:"result"`
+	}
+	json.Unmarshal(data, &resp)
+	forum := resp.Result.IsForum
+	s.mu.Lock()
+	s.forums[chatID] = forumCheck{forum: forum, checked: time.Now()}
+	s.mu.Unlock()
+	return forum
+}
+
+// sessionRootStore maps session IDs to the first session ID of their resume
+// chain, read from the transcript. It is rebuilt from hook events after a
+// restart.
+type sessionRootStore struct {
+	mu    sync.Mutex
+	roots map[string]string
+}
+
+var sessionRoots = &sessionRootStore{roots: make(map[string]string)}
+
+func (s *sessionRootStore) get(sessionID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	root, ok := s.roots[sessionID]
+	return root, ok
+}
+
+func (s *sessionRootStore) set(sessionID, root string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roots[sessionID] = root
+}
+
+// paneLocationStore remembers the last "session:window.pane" location seen
+// for each pane, so a route can still be re-keyed after its pane is gone and
+// routing lookups don't ask tmux every time.
+type paneLocationStore struct {
+	mu        sync.Mutex
+	locations map[string]paneLocationEntry // pane ID → location
+}
+
+type paneLocationEntry struct {
+	location string
+	seen     time.Time
+}
+
+var paneLocations = &paneLocationStore{locations: make(map[string]paneLocationEntry)}
+
+// get returns the last location seen for a pane and when it was seen.
+func (s *paneLocationStore) get(paneID string) (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.locations[paneID]
+	return e.location, e.seen
+}
+
+func (s *paneLocationStore) set(paneID, location string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locations[paneID] = paneLocationEntry{location: location, seen: time.Now()}
+}
+
+// sessionAliasStore holds the user-assigned aliases of sessions, keyed by
+// the first session ID of their resume chain. It is persisted to
+// <config-dir>/aliases.json.
+type sessionAliasStore struct {
+	mu      sync.Mutex
+	aliases map[string]string // root session ID → alias
+}
+
+var sessionAliases = &sessionAliasStore{aliases: make(map[string]string)}
+
+func sessionAliasesPath() string {
+	return filepath.Join(config.GetConfigDir(), "aliases.json")
+}
+
+func (s *sessionAliasStore) load() {
+	data, err := os.ReadFile(sessionAliasesPath())
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := json.Unmarshal(data, &s.aliases); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse aliases.json: %v", err))
+	}
+	if s.aliases == nil {
+		s.aliases = make(map[string]string)
+	}
+}
+
+func (s *sessionAliasStore) get(root string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.aliases[root]
+}
+
+// set assigns alias to the chain root, taking it from any other session.
+func (s *sessionAliasStore) set(root, alias string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for r, a := range s.aliases {
+		if a == alias {
+			delete(s.aliases, r)
+		}
+	}
+	s.aliases[root] = alias
+	data, _ := json.MarshalIndent(s.aliases, "", "  ")
+	if err := os.WriteFile(sessionAliasesPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save aliases.json: %v", err))
+	}
+}
+
+// activeSessionStore holds the session picked with /bot_use in groups with
+// several bound sessions, for the whole chat or for one user in it. Sessions
+// are stored by route key so the choice survives tmux restarts. It is
+// persisted to <config-dir>/active_sessions.json.
+type activeSessionStore struct {
+	mu     sync.Mutex
+	active map[string]string // "chat" or "chat:user" → route key
+	noted  map[string]string // "chat:user" → pane last named in a 📌 note
+}
+
+var activeSessions = &activeSessionStore{active: make(map[string]string), noted: make(map[string]string)}
+
+func activeSessionsPath() string {
+	return filepath.Join(config.GetConfigDir(), "active_sessions.json")
+}
+
+// activeSessionKey returns the store key for a chat (userID 0) or a user in it.
+func activeSessionKey(chatID, userID int64) string {
+	if userID == 0 {
+		return strconv.FormatInt(chatID, 10)
+	}
+	return fmt.Sprintf("%d:%d", chatID, userID)
+}
+
+func (s *activeSessionStore) load() {
+	data, err := os.ReadFile(activeSessionsPath())
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := json.Unmarshal(data, &s.active); err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse active_sessions.json: %v", err))
+	}
+	if s.active == nil {
+		s.active = make(map[string]string)
+	}
+}
+
+// get returns the route key picked by the user, else the one picked for the
+// chat. perUser tells which it was.
+func (s *activeSessionStore) get(chatID, userID int64) (key string, perUser bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if userID != 0 {
+		if key, ok := s.active[activeSessionKey(chatID, userID)]; ok {
+			return key, true, true
+		}
+	}
+	key, ok = s.active[activeSessionKey(chatID, 0)]
+	return key, false, ok
+}
+
+// set picks key for the chat (userID 0) or the user; an empty key clears it.
+func (s *activeSessionStore) set(chatID, userID int64, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key == "" {
+		delete(s.active, activeSessionKey(chatID, userID))
+	} else {
+		s.active[activeSessionKey(chatID, userID)] = key
+	}
+	s.saveLocked()
+}
+
+// noteChanged records that input of userID in chatID went to pane and
+// reports whether that differs from where the user's last input went.
+func (s *activeSessionStore) noteChanged(chatID, userID int64, pane string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := fmt.Sprintf("%d:%d", chatID, userID)
+	if s.noted[k] == pane {
+		return false
+	}
+	s.noted[k] = pane
+	return true
+}
+
+// moveChat moves the picks made in chat from to chat to, or drops them when
+// to is 0.
+func (s *activeSessionStore) moveChat(from, to int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := strconv.FormatInt(from, 10)
+	changed := false
+	for k, key := range s.active {
+		chat, user, _ := strings.Cut(k, ":")
+		if chat != prefix {
+			continue
+		}
+		delete(s.active, k)
+		if to != 0 {
+			nk := strconv.FormatInt(to, 10)
+			if user != "" {
+				nk += ":" + user
+			}
+			s.active[nk] = key
+		}
+		changed = true
+	}
+	if changed {
+		s.saveLocked()
+	}
+}
+
+func (s *activeSessionStore) saveLocked() {
+	data, _ := json.MarshalIndent(s.active, "", "  ")
+	if err := os.WriteFile(activeSessionsPath(), data, 0600); err != nil {
+		logger.Error(fmt.Sprintf("Failed to save active_sessions.json: %v", err))
+	}
+}
+
+// useViewStore keeps the sessions listed in each /bot_use message, in
+// button order.
+type useViewStore struct {
+	mu    sync.Mutex
+	views map[string][]string // chat:msg key → tmux targets
+}
+
+var useViews = &useViewStore{views: make(map[string][]string)}
+
+// joinViews keeps the sessions offered by the binding helper posted when
+// the bot joins a group.
+var joinViews = &useViewStore{views: make(map[string][]string)}
+
+func (s *useViewStore) set(chatID int64, msgID int, targets []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.views[msgTargetKey(chatID, msgID)] = targets
+}
+
+func (s *useViewStore) get(chatID int64, msgID int) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.views[msgTargetKey(chatID, msgID)]
+	return v, ok
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	tele "gopkg.in/telebot.v3"
+)
+
+// maxTopicName is Telegram's limit on forum topic names.
+const maxTopicName = 128
+
+// topicCreation tracks the topics being created, so the hooks and pending
+// requests of a session racing for its first topic start only one creation.
+var topicCreation = struct {
+	sync.Mutex
+	running map[string]bool
+}{running: make(map[string]bool)}
+
+func topicKey(chatID int64, sessionID string) string {
+	return fmt.Sprintf("%d:%s", chatID, sessionID)
+}
+
+// ensureSessionTopic starts creating the forum topic of p's session in chat
+// when forum topics are enabled and chat is a forum supergroup. It doesn't
+// wait: messages sent before the topic exists go to General, later ones to
+// the topic. It is a no-op if the session already has a topic there.
+func ensureSessionTopic(bot *tele.Bot, chat *tele.Chat, p *hookPayload) {
+	if chat == nil || p.SessionID == "" || p.TmuxTarget == "" {
+		return
+	}
+	if sessionTopics.thread(chat.ID, p.SessionID) != 0 {
+		return
+	}
+	cfg, _ := config.LoadAppConfig()
+	if !cfg.ForumTopics {
+		return
+	}
+	key := topicKey(chat.ID, p.SessionID)
+	topicCreation.Lock()
+	if topicCreation.running[key] {
+		topicCreation.Unlock()
+		return
+	}
+	topicCreation.running[key] = true
+	topicCreation.Unlock()
+	go createSessionTopic(bot, chat.ID, p.SessionID, p.Project, p.TmuxTarget)
+}
+
+// createSessionTopic creates a session's topic. If the session ended in the
+// meantime, the new topic is closed right away.
+func createSessionTopic(bot *tele.Bot, chatID int64, sessionID, project, tmuxTarget string) {
+	key := topicKey(chatID, sessionID)
+	defer func() {
+		topicCreation.Lock()
+		delete(topicCreation.running, key)
+		topicCreation.Unlock()
+	}()
+	if sessionTopics.thread(chatID, sessionID) != 0 || !sessionTopics.isForum(bot, chatID) {
+		return
+	}
+	chat := &tele.Chat{ID: chatID}
+	name := truncateStr(fmt.Sprintf("%s · %s", project, tmuxTarget), maxTopicName-3)
+	var topic *tele.Topic
+	err := outbox.do(chatID, func() error {
+		var err error
+		topic, err = bot.CreateTopic(chat, &tele.Topic{Name: name})
+		return err
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create forum topic: chat=%d session=%s err=%v", chatID, sessionID, err))
+		return
+	}
+	topicCreation.Lock()
+	ended := !topicCreation.running[key]
+	topicCreation.Unlock()
+	if ended {
+		outbox.do(chatID, func() error {
+			return bot.CloseTopic(chat, &tele.Topic{ThreadID: topic.ThreadID})
+		})
+		logger.Info(fmt.Sprintf("Forum topic closed, session ended while creating it: chat=%d thread=%d session=%s", chatID, topic.ThreadID, sessionID))
+		return
+	}
+	sessionTopics.set(sessionTopic{ChatID: chatID, SessionID: sessionID, ThreadID: topic.ThreadID, TmuxTarget: tmuxTarget})
+	logger.Info(fmt.Sprintf("Forum topic created: chat=%d thread=%d session=%s tmux=%s", chatID, topic.ThreadID, sessionID, tmuxTarget))
+}
+
+// closeSessionTopic closes the forum topic of an ended session. The close is
+// queued behind the session's last messages so the report lands first.
+func closeSessionTopic(bot *tele.Bot, chat *tele.Chat, sessionID string) {
+	if chat == nil || sessionID == "" {
+		return
+	}
+	// A creation still running sees this and closes its topic itself
+	topicCreation.Lock()
+	delete(topicCreation.running, topicKey(chat.ID, sessionID))
+	topicCreation.Unlock()
+	t, ok := sessionTopics.remove(chat.ID, sessionID)
+	if !ok {
+		return
+	}
+	err := outbox.do(chat.ID, func() error {
+		return bot.CloseTopic(chat, &tele.Topic{ThreadID: t.ThreadID})
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to close forum topic: chat=%d thread=%d err=%v", chat.ID, t.ThreadID, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Forum topic closed: chat=%d thread=%d session=%s", chat.ID, t.ThreadID, sessionID))
+}
+
+// topicThread returns the forum topic a message was posted in, or 0 for the
+// General topic and chats without topics.
+func topicThread(msg *tele.Message) int {
+	if msg == nil || !msg.TopicMessage {
+		return 0
+	}
+	return msg.ThreadID
+}
+
+// isTopicRoot reports whether a message's ReplyTo is only the topic's
+// creation message. Telegram sets it on every message in a topic that isn't
+// an explicit reply.
+func isTopicRoot(msg *tele.Message) bool {
+	return msg != nil && msg.ReplyTo != nil && msg.ReplyTo.TopicCreated != nil
+}
+
+// topicReplies treats a message whose ReplyTo is only its topic's root as not
+// being a reply, so messages in a session topic take the group path and
+// resolve to that session.
+func topicReplies(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if msg := c.Message(); c.Callback() == nil && isTopicRoot(msg) {
+			msg.ReplyTo = nil
+		}
+		return next(c)
+	}
+}
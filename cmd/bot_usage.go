@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/usage"
+	tele "gopkg.in/telebot.v3"
+)
+
+// usageMaxProjects caps the per-project lines of each period.
+const usageMaxProjects = 10
+
+// usageStore holds token and cost totals; opened in runBot.
+var usageStore *usage.Store
+
+func openUsageStore() {
+	usageStore = usage.Open(filepath.Join(config.GetConfigDir(), "usage.json"))
+}
+
+// collectUsage rescans a session transcript into the usage store and records
+// the cumulative cost from the latest statusline snapshot. Subagents pass
+// "<session>/<agent>" as sessionID and carry no cost of their own.
+func collectUsage(sessionID, cwd, transcriptPath string) {
+	if usageStore == nil || sessionID == "" {
+		return
+	}
+	if transcriptPath != "" {
+		f, err := os.Open(transcriptPath)
+		if err == nil {
+			perDay := usage.ScanTranscript(f)
+			f.Close()
+			if err := usageStore.SetTokens(sessionID, cwd, perDay); err != nil {
+				logger.Error(fmt.Sprintf("Failed to save usage: %v", err))
+			}
+		}
+	}
+	if cost, ok := readSessionCost(sessionID); ok {
+		if err := usageStore.AddCost(sessionID, cwd, usage.Day(time.Now()), cost); err != nil {
+			logger.Error(fmt.Sprintf("Failed to save usage cost: %v", err))
+		}
+	}
+	logger.Debug(fmt.Sprintf("Usage collected: session=%s cwd=%s", sessionID, cwd))
+}
+
+// readSessionCost reads the session's cumulative cost saved by `tg-cli statusline`.
+func readSessionCost(sessionID string) (float64, bool) {
+	data, err := os.ReadFile(filepath.Join(os.TempDir(), "tg-cli", "cost", sessionID+".json"))
+	if err != nil {
+		return 0, false
+	}
+	var cost struct {
+		TotalCostUSD *float64 `json:"total_cost_usd"`
+	}
+	if json.Unmarshal(data, &cost) != nil || cost.TotalCostUSD == nil {
+		return 0, false
+	}
+	return *cost.TotalCostUSD, true
+}
+
+// usagePeriod returns the date range of a named period ending today.
+func usagePeriod(name string, now time.Time) (from, to string, ok bool) {
+	to = usage.Day(now)
+	switch name {
+	case "today":
+		return to, to, true
+	case "week":
+		offset := (int(now.Weekday()) + 6) % 7 // days since Monday
+		return usage.Day(now.AddDate(0, 0, -offset)), to, true
+	case "month":
+		return usage.Day(now.AddDate(0, 0, 1-now.Day())), to, true
+	}
+	return "", "", false
+}
+
+// buildUsageText summarizes the given periods, each with a per-project breakdown.
+func buildUsageText(lang, title string, periods []string) string {
+	var sections []string
+	now := time.Now()
+	for _, name := range periods {
+		from, to, _ := usagePeriod(name, now)
+		total, projects := usageStore.Summarize(from, to)
+		lines := []string{i18n.T(lang, "usage.title", i18n.T(lang, "usage."+name))}
+		if total.Total() == 0 && total.CostUSD == 0 {
+			lines = append(lines, i18n.T(lang, "usage.none"))
+			sections = append(sections, strings.Join(lines, "\n"))
+			continue
+		}
+		lines = append(lines, i18n.T(lang, "usage.total",
+			notify.FormatTokens(float64(total.Total())),
+			notify.FormatTokens(float64(total.Input)),
+			notify.FormatTokens(float64(total.Output)),
+			notify.FormatTokens(float64(total.CacheCreation+total.CacheRead))))
+		summary := i18n.N(lang, "usage.sessions", total.Sessions)
+		if total.CostUSD > 0 {
+			summary = i18n.T(lang, "usage.cost", total.CostUSD) + " · " + summary
+		}
+		lines = append(lines, summary)
+		for i, p := range projects {
+			if i == usageMaxProjects {
+				lines = append(lines, i18n.N(lang, "usage.more", len(projects)-usageMaxProjects))
+				break
+			}
+			line := i18n.T(lang, "usage.project", notify.CompressPath(p.Project), notify.FormatTokens(float64(p.Total())))
+			if p.CostUSD > 0 {
+				line += fmt.Sprintf(" · $%.2f", p.CostUSD)
+			}
+			lines = append(lines, line)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	text := strings.Join(sections, "\n\n")
+	if title != "" {
+		text = title + "\n\n" + text
+	}
+	return text
+}
+
+// startUsageReportLoop sends the daily usage report to the default chat at
+// the time configured in config.json (usageReport).
+func startUsageReportLoop(ctx context.Context, bot *tele.Bot) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg, _ := config.LoadAppConfig()
+			if cfg.UsageReport == "" {
+				continue
+			}
+			at, err := time.Parse("15:04", cfg.UsageReport)
+			if err != nil {
+				continue
+			}
+			now := time.Now()
+			today := usage.Day(now)
+			if now.Hour()*60+now.Minute() < at.Hour()*60+at.Minute() || usageStore.LastReport() == today {
+				continue
+			}
+			creds, _ := config.LoadCredentials()
+			chatID, err := strconv.ParseInt(creds.PairingAllow.DefaultChatID, 10, 64)
+			if err != nil {
+				continue
+			}
+			lang := chatLang(chatID)
+			text := buildUsageText(lang, i18n.T(lang, "usage.report_title"), []string{"today", "month"})
+			outbox.post(&tele.Chat{ID: chatID}, 0, text, nil, nil)
+			usageStore.SetLastReport(today)
+			logger.Info(fmt.Sprintf("Daily usage report queued for chat %d", chatID))
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+	"github.com/Seraphli/tg-cli/internal/notify"
+	"github.com/Seraphli/tg-cli/internal/pairing"
+	tele "gopkg.in/telebot.v3"
+)
+
+// activeGroupTarget returns the target among targets picked with /bot_use
+// by the user, or else for the whole chat. A user's pick whose session is no
+// longer bound falls back to the chat's.
+func activeGroupTarget(chatID, userID int64, targets []string) (string, bool, bool) {
+	if userID != 0 {
+		if key, perUser, ok := activeSessions.get(chatID, userID); ok && perUser {
+			if t, ok := matchActive(key, targets); ok {
+				return t, true, true
+			}
+		}
+	}
+	if key, _, ok := activeSessions.get(chatID, 0); ok {
+		if t, ok := matchActive(key, targets); ok {
+			return t, false, true
+		}
+	}
+	return "", false, false
+}
+
+// matchActive returns the target among targets that the picked route key
+// refers to.
+func matchActive(key string, targets []string) (string, bool) {
+	active := routeKeyTarget(key)
+	if active == "" {
+		return "", false
+	}
+	for _, t := range targets {
+		if notify.FormatPaneID(t) == notify.FormatPaneID(active) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// activeRouteKey returns the key an active session is stored by: the
+// session's own key when known, so the pick follows it across resumes.
+func activeRouteKey(tmuxTarget string) string {
+	id := sessionIdentity(tmuxTarget)
+	if key := id.SessionRouteKey(); key != "" {
+		return key
+	}
+	return id.PaneRouteKey()
+}
+
+// sessionLabel renders a session for /bot_use, e.g. "📟 %12 · my-app".
+func sessionLabel(tmuxTarget string) string {
+	label := "📟 " + notify.FormatPaneID(tmuxTarget)
+	if info := sessionState.findInfoByTarget(tmuxTarget); info != nil && info.cwd != "" {
+		label += " · " + filepath.Base(info.cwd)
+	}
+	return label
+}
+
+// activeNote returns the line shown on replies to input that went to the
+// active session of a group with several bound sessions, or "".
+func activeNote(msg *tele.Message, tmuxTarget string) string {
+	if msg.ReplyTo != nil || msg.Sender == nil || topicThread(msg) != 0 {
+		return ""
+	}
+	targets := groupTargets(msg.Chat.ID)
+	if len(targets) < 2 {
+		return ""
+	}
+	t, _, ok := activeGroupTarget(msg.Chat.ID, msg.Sender.ID, targets)
+	if !ok || notify.FormatPaneID(t) != notify.FormatPaneID(tmuxTarget) {
+		return ""
+	}
+	return "📌 " + sessionLabel(t)
+}
+
+// replyActiveNote answers group input that went to the active session with
+// the 📌 line, so it's clear which of the bound sessions got it. The note is
+// sent only when the input goes to a different session than the user's
+// previous input, e.g. after /bot_use or a fallback from a stale pick.
+func replyActiveNote(c tele.Context, tmuxTarget string) {
+	note := activeNote(c.Message(), tmuxTarget)
+	if note == "" || !activeSessions.noteChanged(c.Chat().ID, c.Sender().ID, notify.FormatPaneID(tmuxTarget)) {
+		return
+	}
+	outbox.send(c.Chat(), note, &tele.SendOptions{ReplyTo: c.Message()})
+}
+
+// buildUseMessage lists the sessions bound to a group with buttons to make
+// one active for the chat or just for the user.
+// Callback unique: "use", data: "<index>|chat", "<index>|me" or "clear".
+func buildUseMessage(chatID, userID int64, lang string) (string, *tele.ReplyMarkup, []string) {
+	targets := groupTargets(chatID)
+	lines := []string{i18n.T(lang, "use.title")}
+	chatActive, userActive := "", ""
+	if key, perUser, ok := activeSessions.get(chatID, 0); ok && !perUser {
+		chatActive = notify.FormatPaneID(routeKeyTarget(key))
+	}
+	if key, perUser, ok := activeSessions.get(chatID, userID); ok && perUser {
+		userActive = notify.FormatPaneID(routeKeyTarget(key))
+	}
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for i, t := range targets {
+		label := sessionLabel(t)
+		pane := notify.FormatPaneID(t)
+		mark := ""
+		if pane == chatActive {
+			mark += " 📌"
+		}
+		if pane == userActive {
+			mark += " 👤"
+		}
+		lines = append(lines, label+mark)
+		rows = append(rows, tele.Row{
+			markup.Data(label, "use", strconv.Itoa(i)+"|chat"),
+			markup.Data("👤", "use", strconv.Itoa(i)+"|me"),
+		})
+	}
+	lines = append(lines, "", i18n.T(lang, "use.hint"))
+	rows = append(rows, tele.Row{markup.Data(i18n.T(lang, "use.clear"), "use", "clear")})
+	markup.Inline(rows...)
+	return strings.Join(lines, "\n"), markup, targets
+}
+
+// handleUseCommand handles /bot_use — picks the session plain messages and
+// CC commands go to in a group with several bound sessions.
+func handleUseCommand(c tele.Context) error {
+	if !pairing.IsAllowed(strconv.FormatInt(c.Sender().ID, 10)) {
+		return c.Reply(tr(c, "err.not_paired"))
+	}
+	if c.Chat().Type != tele.ChatGroup && c.Chat().Type != tele.ChatSuperGroup {
+		return c.Reply(tr(c, "use.group_only"))
+	}
+	text, markup, targets := buildUseMessage(c.Chat().ID, c.Sender().ID, ctxLang(c))
+	if len(targets) == 0 {
+		return c.Reply(tr(c, "use.none"))
+	}
+	sent, err := outbox.send(c.Chat(), text, &tele.SendOptions{ReplyTo: c.Message(), ReplyMarkup: markup})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send session picker: %v", err))
+		return nil
+	}
+	useViews.set(c.Chat().ID, sent.ID, targets)
+	return nil
+}
+
+// registerUseCallbacks handles the buttons of /bot_use.
+func registerUseCallbacks(bot *tele.Bot) {
+	bot.Handle(&tele.InlineButton{Unique: "use"}, func(c tele.Context) error {
+		targets, ok := useViews.get(c.Chat().ID, c.Message().ID)
+		if !ok {
+			return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.expired")})
+		}
+		chatID, userID := c.Chat().ID, c.Sender().ID
+		var toast string
+		if c.Data() == "clear" {
+			activeSessions.set(chatID, 0, "")
+			activeSessions.set(chatID, userID, "")
+			toast = tr(c, "use.cleared")
+			logger.Info(fmt.Sprintf("Active session cleared: chat=%d user=%d", chatID, userID))
+		} else {
+			idxStr, scope, _ := strings.Cut(c.Data(), "|")
+			idx, err := strconv.Atoi(idxStr)
+			if err != nil || idx < 0 || idx >= len(targets) || (scope != "chat" && scope != "me") {
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.invalid_data")})
+			}
+			t := targets[idx]
+			if !checkSessionAlive(t, bot) {
+				return c.Respond(&tele.CallbackResponse{Text: tr(c, "cb.session_disconnected")})
+			}
+			key := activeRouteKey(t)
+			if scope == "chat" {
+				activeSessions.set(chatID, 0, key)
+				toast = tr(c, "use.set_chat", sessionLabel(t))
+			} else {
+				activeSessions.set(chatID, userID, key)
+				toast = tr(c, "use.set_me", sessionLabel(t))
+			}
+			logger.Info(fmt.Sprintf("Active session set: chat=%d user=%d scope=%s tmux=%s key=%s", chatID, userID, scope, t, key))
+		}
+		requestOverviewRefresh()
+		text, markup, nt := buildUseMessage(chatID, userID, ctxLang(c))
+		useViews.set(chatID, c.Message().ID, nt)
+		if _, err := outbox.edit(c.Message(), text, markup); err != nil {
+			logger.Debug(fmt.Sprintf("session picker edit error: %v", err))
+		}
+		return c.Respond(&tele.CallbackResponse{Text: toast})
+	})
+}
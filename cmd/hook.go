@@ -120,6 +120,9 @@ func runHook(cmd *cobra.Command, args []string) {
 	hookLog("event=%s", event)
 	// Add computed fields (CC doesn't include these)
 	payload["tmux_target"] = detectTmuxTarget()
+	if route := os.Getenv("TG_CLI_ROUTE"); route != "" {
+		payload["tg_cli_route"] = route
+	}
 	if cwd, ok := payload["cwd"].(string); ok && cwd != "" {
 		payload["project"] = filepath.Base(cwd)
 	}
@@ -25,10 +25,18 @@ func runStatusline(cmd *cobra.Command, args []string) error {
 	var payload struct {
 		SessionID     string          `json:"session_id"`
 		ContextWindow json.RawMessage `json:"context_window"`
+		Cost          json.RawMessage `json:"cost"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return nil
 	}
+	if payload.SessionID != "" && payload.Cost != nil {
+		// Cumulative session cost, picked up by the bot's usage accounting
+		costDir := "/tmp/tg-cli/cost"
+		if err := os.MkdirAll(costDir, 0755); err == nil {
+			os.WriteFile(costDir+"/"+payload.SessionID+".json", payload.Cost, 0644)
+		}
+	}
 	if payload.SessionID == "" || payload.ContextWindow == nil {
 		return nil
 	}
@@ -0,0 +1,301 @@
+// Package ccsettings reads and edits the permission rules in Claude Code
+// settings files.
+package ccsettings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Scope is one of the settings files that hold permission rules.
+type Scope string
+
+const (
+	ScopeUser    Scope = "user"    // ~/.claude/settings.json
+	ScopeProject Scope = "project" // <cwd>/.claude/settings.json
+	ScopeLocal   Scope = "local"   // <cwd>/.claude/settings.local.json
+)
+
+// Scopes lists the scopes from broadest to narrowest.
+var Scopes = []Scope{ScopeUser, ScopeProject, ScopeLocal}
+
+// Lists are the permission lists rules can be in.
+var Lists = []string{"allow", "ask", "deny"}
+
+// ErrNotFound is returned when a rule to remove is not in its file.
+var ErrNotFound = errors.New("rule not found")
+
+// Rule is one permission entry, e.g. "Bash(npm test:*)" in scope user, list allow.
+type Rule struct {
+	Scope Scope
+	List  string
+	Rule  string
+}
+
+// Path returns the settings file of scope for a session in cwd.
+func Path(scope Scope, cwd string) string {
+	switch scope {
+	case ScopeUser:
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".claude", "settings.json")
+	case ScopeProject:
+		return filepath.Join(cwd, ".claude", "settings.json")
+	}
+	return filepath.Join(cwd, ".claude", "settings.local.json")
+}
+
+// Rules returns the permission rules of every scope for cwd, in scope and
+// list order. Missing files are skipped; a file that isn't valid JSON is an error.
+func Rules(cwd string) ([]Rule, error) {
+	var rules []Rule
+	seen := make(map[string]bool)
+	for _, scope := range Scopes {
+		// In the home directory the project file is the user file
+		path := Path(scope, cwd)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		settings, err := read(path)
+		if err != nil {
+			return nil, err
+		}
+		perms, _ := settings["permissions"].(map[string]interface{})
+		for _, list := range Lists {
+			entries, _ := perms[list].([]interface{})
+			for _, e := range entries {
+				if s, ok := e.(string); ok {
+					rules = append(rules, Rule{Scope: scope, List: list, Rule: s})
+				}
+			}
+		}
+	}
+	return rules, nil
+}
+
+// Remove deletes r from its settings file.
+func Remove(cwd string, r Rule) error {
+	return edit(Path(r.Scope, cwd), func(perms map[string][]interface{}) error {
+		entries := perms[r.List]
+		kept := make([]interface{}, 0, len(entries))
+		found := false
+		for _, e := range entries {
+			if s, ok := e.(string); ok && s == r.Rule && !found {
+				found = true
+				continue
+			}
+			kept = append(kept, e)
+		}
+		if !found {
+			return ErrNotFound
+		}
+		perms[r.List] = kept
+		return nil
+	})
+}
+
+// Add appends r to its settings file unless it is already there.
+func Add(cwd string, r Rule) error {
+	return edit(Path(r.Scope, cwd), func(perms map[string][]interface{}) error {
+		entries := perms[r.List]
+		for _, e := range entries {
+			if s, ok := e.(string); ok && s == r.Rule {
+				return nil
+			}
+		}
+		perms[r.List] = append(entries, r.Rule)
+		return nil
+	})
+}
+
+// Move moves r to scope to: it is added there first, then removed from its
+// current file, so a failure never loses the rule.
+func Move(cwd string, r Rule, to Scope) error {
+	if Path(r.Scope, cwd) == Path(to, cwd) {
+		return nil
+	}
+	if err := Add(cwd, Rule{Scope: to, List: r.List, Rule: r.Rule}); err != nil {
+		return err
+	}
+	return Remove(cwd, r)
+}
+
+func read(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings := map[string]interface{}{}
+	if len(data) == 0 {
+		return settings, nil
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// object is a JSON object with its keys in file order and its values as raw
+// JSON, so values that aren't changed are written back as they were read.
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func parseObject(data []byte) (*object, error) {
+	o := &object{values: make(map[string]json.RawMessage)}
+	if t := bytes.TrimSpace(data); len(t) == 0 || string(t) == "null" {
+		return o, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		o.set(tok.(string), raw)
+	}
+	_, err := dec.Token()
+	return o, err
+}
+
+func (o *object) set(key string, raw json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = raw
+}
+
+func (o *object) marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(o.values[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshal encodes v without escaping <, > and &, which are common in rules
+// such as "Bash(make && make test:*)".
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// edit applies fn to the permission lists of the settings at path. Only the
+// lists fn changes are re-encoded; other keys keep their order and content.
+// The previous content is kept in path.bak and the new content is written to
+// a temp file and renamed over the original, so readers never see a partial
+// file.
+func edit(path string, fn func(perms map[string][]interface{}) error) error {
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	doc, err := parseObject(old)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	permsObj, err := parseObject(doc.values["permissions"])
+	if err != nil {
+		return fmt.Errorf("%s: permissions: %w", path, err)
+	}
+	perms := make(map[string][]interface{})
+	before := make(map[string][]interface{})
+	for _, list := range Lists {
+		var entries []interface{}
+		if raw, ok := permsObj.values[list]; ok {
+			json.Unmarshal(raw, &entries)
+			before[list] = append([]interface{}(nil), entries...)
+		}
+		perms[list] = entries
+	}
+	if err := fn(perms); err != nil {
+		return err
+	}
+	changed := false
+	for _, list := range Lists {
+		if reflect.DeepEqual(perms[list], before[list]) {
+			continue
+		}
+		raw, err := marshal(perms[list])
+		if err != nil {
+			return err
+		}
+		permsObj.set(list, raw)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	rawPerms, err := permsObj.marshal()
+	if err != nil {
+		return err
+	}
+	doc.set("permissions", rawPerms)
+	compact, err := doc.marshal()
+	if err != nil {
+		return err
+	}
+	var data bytes.Buffer
+	if err := json.Indent(&data, compact, "", "  "); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		if err := os.WriteFile(path+".bak", old, mode); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data.Bytes(), '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
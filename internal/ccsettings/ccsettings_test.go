@@ -0,0 +1,117 @@
+package ccsettings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSettings(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	data, _ := json.Marshal(v)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRulesAndMove(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cwd := filepath.Join(t.TempDir(), "proj")
+	writeSettings(t, Path(ScopeUser, cwd), map[string]interface{}{
+		"hooks":       map[string]interface{}{"Stop": []interface{}{}},
+		"permissions": map[string]interface{}{"allow": []string{"Bash(ls:*)"}, "deny": []string{"Bash(rm:*)"}},
+	})
+	writeSettings(t, Path(ScopeLocal, cwd), map[string]interface{}{
+		"permissions": map[string]interface{}{"allow": []string{"WebFetch(domain:go.dev)"}},
+	})
+
+	rules, err := Rules(cwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rule{
+		{ScopeUser, "allow", "Bash(ls:*)"},
+		{ScopeUser, "deny", "Bash(rm:*)"},
+		{ScopeLocal, "allow", "WebFetch(domain:go.dev)"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("Rules = %+v", rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	if err := Move(cwd, rules[2], ScopeProject); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(cwd, rules[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(cwd, rules[0]); err != ErrNotFound {
+		t.Errorf("second Remove err = %v, want ErrNotFound", err)
+	}
+	rules, _ = Rules(cwd)
+	want = []Rule{
+		{ScopeUser, "deny", "Bash(rm:*)"},
+		{ScopeProject, "allow", "WebFetch(domain:go.dev)"},
+	}
+	if len(rules) != len(want) || rules[0] != want[0] || rules[1] != want[1] {
+		t.Errorf("after edits Rules = %+v", rules)
+	}
+
+	// Other settings survive, the previous version is backed up and the mode is kept
+	var user map[string]interface{}
+	data, _ := os.ReadFile(Path(ScopeUser, cwd))
+	json.Unmarshal(data, &user)
+	if _, ok := user["hooks"]; !ok {
+		t.Error("hooks dropped from user settings")
+	}
+	if _, err := os.Stat(Path(ScopeUser, cwd) + ".bak"); err != nil {
+		t.Errorf("no backup: %v", err)
+	}
+	if info, _ := os.Stat(Path(ScopeUser, cwd)); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestEditKeepsOtherContent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cwd := t.TempDir()
+	path := Path(ScopeLocal, cwd)
+	os.MkdirAll(filepath.Dir(path), 0755)
+	orig := `{
+  "model": "opus",
+  "permissions": {
+    "deny": [
+      "Read(.env)"
+    ],
+    "allow": [
+      "Bash(make && make test:*)",
+      "Bash(ls:*)"
+    ]
+  },
+  "env": {
+    "Z": "1",
+    "A": "<x>"
+  }
+}
+`
+	if err := os.WriteFile(path, []byte(orig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(cwd, Rule{ScopeLocal, "allow", "Bash(ls:*)"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Add(cwd, Rule{ScopeLocal, "allow", "Bash(ls:*)"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != orig {
+		t.Errorf("settings after remove and add =\n%s\nwant\n%s", data, orig)
+	}
+}
@@ -4,14 +4,145 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type Credentials struct {
-	BotToken        string           `json:"botToken"`
-	PairingAllow    PairingAllow     `json:"pairingAllow"`
-	Port            int              `json:"port"`
-	RouteMap        map[string]int64 `json:"routeMap,omitempty"`
-	ProjectRouteMap map[string]int64 `json:"projectRouteMap,omitempty"`
+	BotToken        string            `json:"botToken"`
+	PairingAllow    PairingAllow      `json:"pairingAllow"`
+	Port            int               `json:"port"`
+	RouteMap        map[string]int64  `json:"routeMap,omitempty"`
+	ProjectRouteMap map[string]int64  `json:"projectRouteMap,omitempty"`
+	Languages       map[string]string `json:"languages,omitempty"` // chat or user ID -> language code
+	// RouteRules are checked in order after RouteMap and ProjectRouteMap;
+	// the first matching rule routes the session.
+	RouteRules []RouteRule `json:"routeRules,omitempty"`
+	// FanOut copies a session's messages to more chats than the one it is
+	// routed to; every matching entry applies.
+	FanOut []FanOutRoute `json:"fanOut,omitempty"`
+}
+
+// RouteRule routes the sessions matching all of its set conditions to Chat.
+// CWD is a directory prefix or a glob; the other fields are globs where "*"
+// matches any text. A rule without conditions matches nothing.
+type RouteRule struct {
+	Name        string `json:"name,omitempty"`
+	CWD         string `json:"cwd,omitempty"`
+	Remote      string `json:"remote,omitempty"`      // git remote "origin" URL
+	Branch      string `json:"branch,omitempty"`      // current git branch
+	TmuxSession string `json:"tmuxSession,omitempty"` // tmux session name
+	TmuxWindow  string `json:"tmuxWindow,omitempty"`  // tmux window name
+	Env         string `json:"env,omitempty"`         // TG_CLI_ROUTE of the session
+	Chat        int64  `json:"chat"`
+}
+
+// FanOutRoute copies the events listed in Events of the sessions it matches
+// to Chat. A session matches when it carries Key (a RouteMap key such as
+// "alias:api") and meets the conditions of RouteRule; at least one of the two
+// must be set.
+type FanOutRoute struct {
+	RouteRule
+	Key string `json:"key,omitempty"`
+	// Events are hook event names, e.g. "Stop" or "PermissionRequest" (which
+	// includes plan approvals) and "AskUserQuestion"; empty copies all.
+	Events []string `json:"events,omitempty"`
+}
+
+// Copies reports whether f copies messages of event.
+func (f FanOutRoute) Copies(event string) bool {
+	if len(f.Events) == 0 {
+		return true
+	}
+	for _, e := range f.Events {
+		if strings.EqualFold(e, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateChat points every route, rule, fan-out copy, language and pairing
+// entry of chat from at chat to, as when a group becomes a supergroup. It
+// returns the number of routes, rules and fan-out copies moved.
+func (c *Credentials) MigrateChat(from, to int64) int {
+	n := 0
+	for k, id := range c.RouteMap {
+		if id == from {
+			c.RouteMap[k] = to
+			n++
+		}
+	}
+	for k, id := range c.ProjectRouteMap {
+		if id == from {
+			c.ProjectRouteMap[k] = to
+			n++
+		}
+	}
+	for i := range c.RouteRules {
+		if c.RouteRules[i].Chat == from {
+			c.RouteRules[i].Chat = to
+			n++
+		}
+	}
+	for i := range c.FanOut {
+		if c.FanOut[i].Chat == from {
+			c.FanOut[i].Chat = to
+			n++
+		}
+	}
+	fromID, toID := strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)
+	if lang, ok := c.Languages[fromID]; ok {
+		delete(c.Languages, fromID)
+		c.Languages[toID] = lang
+	}
+	for i, id := range c.PairingAllow.IDs {
+		if id == fromID {
+			c.PairingAllow.IDs[i] = toID
+		}
+	}
+	if c.PairingAllow.DefaultChatID == fromID {
+		c.PairingAllow.DefaultChatID = toID
+	}
+	return n
+}
+
+// DropChat removes the routes, rules and fan-out copies that send to chat
+// and returns how many were removed.
+func (c *Credentials) DropChat(chat int64) int {
+	n := 0
+	for k, id := range c.RouteMap {
+		if id == chat {
+			delete(c.RouteMap, k)
+			n++
+		}
+	}
+	for k, id := range c.ProjectRouteMap {
+		if id == chat {
+			delete(c.ProjectRouteMap, k)
+			n++
+		}
+	}
+	rules := c.RouteRules[:0]
+	for _, r := range c.RouteRules {
+		if r.Chat == chat {
+			n++
+			continue
+		}
+		rules = append(rules, r)
+	}
+	c.RouteRules = rules
+	fanOut := c.FanOut[:0]
+	for _, f := range c.FanOut {
+		if f.Chat == chat {
+			n++
+			continue
+		}
+		fanOut = append(fanOut, f)
+	}
+	c.FanOut = fanOut
+	return n
 }
 
 type PairingAllow struct {
@@ -73,6 +204,9 @@ func LoadCredentials() (Credentials, error) {
 	if creds.ProjectRouteMap == nil {
 		creds.ProjectRouteMap = make(map[string]int64)
 	}
+	if creds.Languages == nil {
+		creds.Languages = make(map[string]string)
+	}
 	return creds, nil
 }
 
@@ -98,6 +232,126 @@ type AppConfig struct {
 	FFmpegPath    string `json:"ffmpegPath"`
 	WhisperPrompt string `json:"whisperPrompt"`
 	VoicePrefix   string `json:"voicePrefix"`
+	// Verbosity per optional hook event (Notification, SubagentStop,
+	// PreCompact, PostToolUse); see EventVerbosity.
+	Verbosity map[string]string `json:"verbosity,omitempty"`
+	// UsageReport is the local time ("HH:MM") of the daily usage report sent
+	// to the default chat; empty disables it.
+	UsageReport string `json:"usageReport,omitempty"`
+	// ContextAlerts are the context usage percentages (of the auto-compact
+	// limit) that trigger an alert; nil means the defaults, [] disables them.
+	ContextAlerts []int `json:"contextAlerts"`
+	// Overview enables the pinned per-chat session overview (default true).
+	Overview *bool `json:"overview,omitempty"`
+	// PermissionTimeout is the reminder and deadline policy for unanswered
+	// permission requests; ProjectPermissionTimeouts overrides it per project
+	// directory. See PermissionTimeoutFor.
+	PermissionTimeout         *PermissionTimeout           `json:"permissionTimeout,omitempty"`
+	ProjectPermissionTimeouts map[string]PermissionTimeout `json:"projectPermissionTimeouts,omitempty"`
+	// ForumTopics gives every session its own topic when its chat is a
+	// forum supergroup.
+	ForumTopics bool `json:"forumTopics,omitempty"`
+	// MirrorPrompts posts prompts typed at the terminal to the session's
+	// chat. Prompts sent from Telegram are not posted again.
+	MirrorPrompts bool `json:"mirrorPrompts,omitempty"`
+}
+
+// Actions taken when a permission request reaches its deadline.
+const (
+	PermActionDeny    = "deny"
+	PermActionAllow   = "allow"
+	PermActionPending = "pending" // stop reminding and leave it unanswered
+)
+
+// PermissionTimeout controls reminders and the default answer for a
+// permission request nobody answers. All durations are in minutes.
+type PermissionTimeout struct {
+	RemindEvery int      `json:"remindEvery,omitempty"` // 0 disables reminders
+	Mention     []string `json:"mention,omitempty"`     // appended to reminders, e.g. "@alice"
+	Deadline    int      `json:"deadline,omitempty"`    // 0 means never
+	Action      string   `json:"action,omitempty"`      // deny (default), allow or pending
+	Message     string   `json:"message,omitempty"`     // reason given to Claude on deny
+}
+
+// DeadlineAction returns the action to take at the deadline.
+func (t PermissionTimeout) DeadlineAction() string {
+	switch t.Action {
+	case PermActionAllow, PermActionPending:
+		return t.Action
+	}
+	return PermActionDeny
+}
+
+// PermissionTimeoutFor returns the policy for a session in cwd: the entry of
+// ProjectPermissionTimeouts for the deepest directory containing cwd ("~/"
+// is expanded), else PermissionTimeout, else no reminders or deadline.
+func (c AppConfig) PermissionTimeoutFor(cwd string) PermissionTimeout {
+	home, _ := os.UserHomeDir()
+	best := -1
+	var policy PermissionTimeout
+	for dir, t := range c.ProjectPermissionTimeouts {
+		if strings.HasPrefix(dir, "~/") && home != "" {
+			dir = filepath.Join(home, dir[2:])
+		}
+		dir = filepath.Clean(dir)
+		if (cwd == dir || strings.HasPrefix(cwd, dir+string(filepath.Separator))) && len(dir) > best {
+			best = len(dir)
+			policy = t
+		}
+	}
+	if best >= 0 {
+		return policy
+	}
+	if c.PermissionTimeout != nil {
+		return *c.PermissionTimeout
+	}
+	return PermissionTimeout{}
+}
+
+// OverviewEnabled reports whether the pinned chat overview is enabled.
+func (c AppConfig) OverviewEnabled() bool {
+	return c.Overview == nil || *c.Overview
+}
+
+// DefaultContextAlerts are the thresholds used when ContextAlerts is unset.
+var DefaultContextAlerts = []int{70, 90}
+
+// ContextAlertThresholds returns the configured thresholds in ascending order.
+func (c AppConfig) ContextAlertThresholds() []int {
+	if c.ContextAlerts == nil {
+		return DefaultContextAlerts
+	}
+	t := append([]int(nil), c.ContextAlerts...)
+	sort.Ints(t)
+	return t
+}
+
+// Verbosity levels for optional hook events, from quietest to most detailed.
+const (
+	VerbosityOff    = "off"
+	VerbosityErrors = "errors" // failures only
+	VerbosityBrief  = "brief"  // truncated body
+	VerbosityFull   = "full"   // complete body, paginated
+)
+
+var defaultVerbosity = map[string]string{
+	"Notification": VerbosityBrief,
+	"SubagentStop": VerbosityBrief,
+	"PreCompact":   VerbosityBrief,
+	"PostToolUse":  VerbosityErrors,
+}
+
+// EventVerbosity returns the configured verbosity for event, or its default
+// when unset or invalid.
+func (c AppConfig) EventVerbosity(event string) string {
+	switch v := c.Verbosity[event]; v {
+	case VerbosityOff, VerbosityErrors, VerbosityBrief, VerbosityFull:
+		return v
+	}
+	if v, ok := defaultVerbosity[event]; ok {
+		return v
+	}
+	return VerbosityFull
 }
 
 func GetConfigPath() string {
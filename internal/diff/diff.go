@@ -0,0 +1,252 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind classifies a line in a diff.
+type Kind int
+
+const (
+	Equal Kind = iota
+	Delete
+	Insert
+)
+
+// Line is one line of a line-based diff. OldNum/NewNum are 1-based line
+// numbers in the old and new text; 0 means the line does not exist there.
+type Line struct {
+	Kind   Kind
+	Text   string
+	OldNum int
+	NewNum int
+}
+
+// Hunk is a run of changes with surrounding context, as in unified diff.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// maxLCSCells bounds the LCS table; larger inputs fall back to a plain
+// delete-then-insert of the differing middle section.
+const maxLCSCells = 4_000_000
+
+// SplitLines splits text into lines without the trailing newline.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+// Lines computes a line diff between a and b.
+func Lines(a, b []string) []Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var out []Line
+	oldNum, newNum := 0, 0
+	emit := func(k Kind, text string) {
+		l := Line{Kind: k, Text: text}
+		if k != Insert {
+			oldNum++
+			l.OldNum = oldNum
+		}
+		if k != Delete {
+			newNum++
+			l.NewNum = newNum
+		}
+		out = append(out, l)
+	}
+	for i := 0; i < prefix; i++ {
+		emit(Equal, a[i])
+	}
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	for _, op := range lcsOps(midA, midB) {
+		emit(op.Kind, op.Text)
+	}
+	for i := len(a) - suffix; i < len(a); i++ {
+		emit(Equal, a[i])
+	}
+	return out
+}
+
+func lcsOps(a, b []string) []Line {
+	n, m := len(a), len(b)
+	var ops []Line
+	if n == 0 || m == 0 || n*m > maxLCSCells {
+		for _, s := range a {
+			ops = append(ops, Line{Kind: Delete, Text: s})
+		}
+		for _, s := range b {
+			ops = append(ops, Line{Kind: Insert, Text: s})
+		}
+		return ops
+	}
+	// table[i][j] = LCS length of a[i:] and b[j:]
+	table := make([][]int32, n+1)
+	for i := range table {
+		table[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else if table[i+1][j] >= table[i][j+1] {
+				table[i][j] = table[i+1][j]
+			} else {
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Line{Kind: Equal, Text: a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			ops = append(ops, Line{Kind: Delete, Text: a[i]})
+			i++
+		default:
+			ops = append(ops, Line{Kind: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, Line{Kind: Delete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, Line{Kind: Insert, Text: b[j]})
+	}
+	return ops
+}
+
+// Hunks groups changed lines with up to context lines of surrounding context.
+func Hunks(lines []Line, context int) []Hunk {
+	var hunks []Hunk
+	i := 0
+	for i < len(lines) {
+		if lines[i].Kind == Equal {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// Extend while the gap between changes is small enough to merge.
+		end := i
+		for end < len(lines) {
+			if lines[end].Kind != Equal {
+				end++
+				continue
+			}
+			gap := end
+			for gap < len(lines) && lines[gap].Kind == Equal {
+				gap++
+			}
+			if gap == len(lines) || gap-end > 2*context {
+				end += min(context, gap-end)
+				break
+			}
+			end = gap
+		}
+		hunks = append(hunks, newHunk(lines[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+func newHunk(lines []Line) Hunk {
+	h := Hunk{Lines: lines}
+	for _, l := range lines {
+		if l.OldNum > 0 {
+			if h.OldStart == 0 {
+				h.OldStart = l.OldNum
+			}
+			h.OldLines++
+		}
+		if l.NewNum > 0 {
+			if h.NewStart == 0 {
+				h.NewStart = l.NewNum
+			}
+			h.NewLines++
+		}
+	}
+	// Unified diff convention: an empty side starts at the line before.
+	if h.OldLines == 0 && len(lines) > 0 {
+		h.OldStart = precedingNum(lines[0].NewNum, h.NewStart)
+	}
+	if h.NewLines == 0 && len(lines) > 0 {
+		h.NewStart = precedingNum(lines[0].OldNum, h.OldStart)
+	}
+	return h
+}
+
+func precedingNum(num, fallback int) int {
+	if num > 0 {
+		return num - 1
+	}
+	return fallback
+}
+
+// Header returns the "@@ -a,b +c,d @@" line for the hunk.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// Stats counts inserted and deleted lines.
+func Stats(lines []Line) (added, removed int) {
+	for _, l := range lines {
+		switch l.Kind {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// Unified renders a standard unified diff between oldText and newText.
+// It returns "" when the texts are identical.
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	hunks := Hunks(Lines(SplitLines(oldText), SplitLines(newText)), context)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for _, h := range hunks {
+		sb.WriteString(h.Header() + "\n")
+		for _, l := range h.Lines {
+			sb.WriteString(prefix(l.Kind) + l.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func prefix(k Kind) string {
+	switch k {
+	case Delete:
+		return "-"
+	case Insert:
+		return "+"
+	}
+	return " "
+}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name   string
+		old    string
+		new    string
+		expect string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{"replace_middle", "a\nb\nc\n", "a\nx\nc\n",
+			"--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"append", "a\n", "a\nb\n",
+			"--- a\n+++ b\n@@ -1,1 +1,2 @@\n a\n+b\n"},
+		{"new_file", "", "a\nb\n",
+			"--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"delete_all", "a\n", "",
+			"--- a\n+++ b\n@@ -1,1 +0,0 @@\n-a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("a", "b", tt.old, tt.new, 3)
+			if got != tt.expect {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestHunksSplitOnLargeGap(t *testing.T) {
+	old := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	new := []string{"x", "2", "3", "4", "5", "6", "7", "8", "9", "y"}
+	hunks := Hunks(Lines(old, new), 1)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	if h := hunks[0].Header(); h != "@@ -1,2 +1,2 @@" {
+		t.Errorf("first hunk header = %s", h)
+	}
+	if h := hunks[1].Header(); h != "@@ -9,2 +9,2 @@" {
+		t.Errorf("second hunk header = %s", h)
+	}
+	merged := Hunks(Lines(old, new), 4)
+	if len(merged) != 1 {
+		t.Errorf("got %d hunks with context 4, want 1", len(merged))
+	}
+}
+
+func TestLinesNumbers(t *testing.T) {
+	lines := Lines([]string{"a", "b"}, []string{"b", "c"})
+	added, removed := Stats(lines)
+	if added != 1 || removed != 1 {
+		t.Fatalf("Stats() = +%d -%d, want +1 -1", added, removed)
+	}
+	for _, l := range lines {
+		if l.Text == "b" && (l.OldNum != 2 || l.NewNum != 1) {
+			t.Errorf("line b numbers = %d/%d, want 2/1", l.OldNum, l.NewNum)
+		}
+	}
+}
+
+func TestParseUnified(t *testing.T) {
+	in := "diff --git a/x.go b/x.go\n" +
+		"index 1..2 100644\n" +
+		"--- a/x.go\n" +
+		"+++ b/x.go\n" +
+		"@@ -3,3 +3,4 @@ func main() {\n" +
+		" a\n" +
+		"-b\n" +
+		"+c\n" +
+		"+--- d\n" +
+		" e\n" +
+		"diff --git a/new.txt b/new.txt\n" +
+		"new file mode 100644\n" +
+		"--- /dev/null\n" +
+		"+++ b/new.txt\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+hi\n" +
+		"\\ No newline at end of file\n" +
+		"diff --git a/img.png b/img.png\n" +
+		"deleted file mode 100644\n" +
+		"Binary files a/img.png and /dev/null differ\n"
+	files := ParseUnified(in)
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want 3", len(files))
+	}
+	f := files[0]
+	if f.Path() != "x.go" || len(f.Hunks) != 1 {
+		t.Fatalf("file 0 = %+v", f)
+	}
+	want := []Line{
+		{Equal, "a", 3, 3}, {Delete, "b", 4, 0}, {Insert, "c", 0, 4}, {Insert, "--- d", 0, 5}, {Equal, "e", 5, 6},
+	}
+	if len(f.Hunks[0].Lines) != len(want) {
+		t.Fatalf("hunk lines = %+v", f.Hunks[0].Lines)
+	}
+	for i, l := range want {
+		if f.Hunks[0].Lines[i] != l {
+			t.Errorf("line %d = %+v, want %+v", i, f.Hunks[0].Lines[i], l)
+		}
+	}
+	if added, removed := Stats(f.Hunks[0].Lines); added != 2 || removed != 1 {
+		t.Errorf("Stats = +%d -%d", added, removed)
+	}
+	if n := files[1]; !n.NewFile || n.Path() != "new.txt" || len(n.Hunks) != 1 || len(n.Hunks[0].Lines) != 1 {
+		t.Errorf("file 1 = %+v", n)
+	}
+	if b := files[2]; !b.Binary || !b.Deleted || b.Path() != "img.png" {
+		t.Errorf("file 2 = %+v", b)
+	}
+	if !strings.HasPrefix(files[1].Raw, "diff --git a/new.txt") || !strings.HasSuffix(files[1].Raw, "file\n") {
+		t.Errorf("file 1 raw = %q", files[1].Raw)
+	}
+}
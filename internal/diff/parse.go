@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FileDiff is one file's section of a git-style unified diff.
+type FileDiff struct {
+	OldPath string // "" for new files
+	NewPath string // "" for deleted files
+	NewFile bool
+	Deleted bool
+	Binary  bool
+	Hunks   []Hunk
+	Raw     string // the file's section of the input, headers included
+}
+
+// Path returns the file's current path, or its old path when deleted.
+func (f FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// ParseUnified splits a unified diff (as printed by git diff or git show)
+// into files and numbered hunks. Lines it doesn't understand are skipped.
+func ParseUnified(text string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var raw []string
+	flush := func() {
+		if cur != nil {
+			cur.Raw = strings.Join(raw, "\n") + "\n"
+			files = append(files, *cur)
+		}
+		cur, raw = nil, nil
+	}
+	lines := SplitLines(text)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		// Plain unified diffs have no "diff --git" line; a "---" after hunks starts the next file
+		if strings.HasPrefix(line, "diff --git ") || (strings.HasPrefix(line, "--- ") && (cur == nil || len(cur.Hunks) > 0)) {
+			flush()
+			cur = &FileDiff{}
+			if strings.HasPrefix(line, "diff --git ") {
+				cur.OldPath, cur.NewPath = gitHeaderPaths(strings.TrimPrefix(line, "diff --git "))
+			}
+		}
+		if cur == nil {
+			continue
+		}
+		raw = append(raw, line)
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			cur.NewFile = true
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Deleted = true
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			cur.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			cur.OldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+			cur.NewFile = cur.OldPath == ""
+		case strings.HasPrefix(line, "+++ "):
+			cur.NewPath = diffPath(strings.TrimPrefix(line, "+++ "), "b/")
+			cur.Deleted = cur.NewPath == ""
+		case strings.HasPrefix(line, "@@ "):
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			oldNum, newNum := h.OldStart, h.NewStart
+			oldLeft, newLeft := h.OldLines, h.NewLines
+			for (oldLeft > 0 || newLeft > 0) && i+1 < len(lines) {
+				body := lines[i+1]
+				if strings.HasPrefix(body, `\`) {
+					// "\ No newline at end of file"
+					raw = append(raw, body)
+					i++
+					continue
+				}
+				l := Line{Text: body}
+				if body != "" {
+					l.Text = body[1:]
+				}
+				switch {
+				case strings.HasPrefix(body, "-") && oldLeft > 0:
+					l.Kind, l.OldNum = Delete, oldNum
+					oldNum++
+					oldLeft--
+				case strings.HasPrefix(body, "+") && newLeft > 0:
+					l.Kind, l.NewNum = Insert, newNum
+					newNum++
+					newLeft--
+				case (body == "" || strings.HasPrefix(body, " ")) && oldLeft > 0 && newLeft > 0:
+					l.Kind, l.OldNum, l.NewNum = Equal, oldNum, newNum
+					oldNum++
+					newNum++
+					oldLeft--
+					newLeft--
+				default:
+					oldLeft, newLeft = 0, 0
+					continue
+				}
+				h.Lines = append(h.Lines, l)
+				raw = append(raw, body)
+				i++
+			}
+			cur.Hunks = append(cur.Hunks, h)
+		}
+	}
+	flush()
+	return files
+}
+
+// parseHunkHeader reads "@@ -a,b +c,d @@"; a missing count means 1.
+func parseHunkHeader(line string) (Hunk, bool) {
+	var h Hunk
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" {
+		return h, false
+	}
+	var ok1, ok2 bool
+	h.OldStart, h.OldLines, ok1 = parseRange(strings.TrimPrefix(fields[1], "-"))
+	h.NewStart, h.NewLines, ok2 = parseRange(strings.TrimPrefix(fields[2], "+"))
+	return h, ok1 && ok2 && fields[1][0] == '-' && fields[2][0] == '+'
+}
+
+func parseRange(s string) (start, count int, ok bool) {
+	count = 1
+	startStr, countStr, found := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	if found {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// diffPath strips the a/ or b/ prefix from a ---/+++ path; /dev/null is "".
+func diffPath(s, prefix string) string {
+	if i := strings.Index(s, "\t"); i != -1 {
+		s = s[:i]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// gitHeaderPaths reads "a/x b/y" from a diff --git line. Paths with spaces
+// are ambiguous there; the ---/+++ lines that follow override them.
+func gitHeaderPaths(s string) (string, string) {
+	if i := strings.Index(s, " b/"); i != -1 {
+		return strings.TrimPrefix(s[:i], "a/"), s[i+3:]
+	}
+	return "", ""
+}
@@ -0,0 +1,288 @@
+package i18n
+
+var en = map[string]string{
+	// Notifications
+	"notify.session_started":      "🟢 Session Started",
+	"notify.session_ended":        "🔴 Session Ended",
+	"notify.update":               "💬 Update",
+	"notify.task_completed":       "✅ Task Completed",
+	"notify.attention":            "🔔 Needs Attention",
+	"notify.subagent_done":        "🤖 Subagent Finished",
+	"notify.compact_auto":         "🗜️ Auto-compacting Context",
+	"notify.compact_manual":       "🗜️ Compacting Context",
+	"notify.tool_done":            "🔧 %s Done",
+	"notify.tool_failed":          "⚠️ %s Failed",
+	"notify.compact_instructions": "Instructions:",
+	"notify.project":              "Project: %s",
+	"notify.context":              "📊 Context: %d%% (%s/%s)",
+	"notify.claude":               "💬 Claude:",
+	"notify.you":                  "👤 You: %s",
+
+	// TodoWrite checklist
+	"todo.title": "📋 Tasks: %d/%d done",
+	"todo.empty": "(no tasks)",
+
+	// Context window alerts
+	"context.alert":   "⚠️ Context %d%% full",
+	"context.hint":    "Compact or clear the conversation, or reply with a focus to run /compact <focus>.",
+	"context.compact": "🗜 /compact",
+	"context.clear":   "🧹 /clear",
+	"context.sent":    "✅ %s sent",
+
+	// Permission requests
+	"perm.title":            "🔐 Permission Request",
+	"perm.tool":             "🔧 Tool: %s",
+	"perm.mcp":              "🔌 MCP: %s",
+	"perm.allow":            "Allow",
+	"perm.deny":             "Deny",
+	"perm.always_allow":     "Always Allow",
+	"perm.allow_dir":        "Allow dir: %s",
+	"perm.edit":             "Edit",
+	"perm.edit_prompt":      "✏️ Reply to this message with the new %s. Current value:",
+	"perm.edit_expired":     "❌ This permission request is no longer pending.",
+	"perm.edited":           "✏️ Allowed with edited %s",
+	"perm.edit_original":    "Original:",
+	"perm.edit_new":         "Edited:",
+	"perm.reminder":         "⏰ Permission request waiting for %s",
+	"perm.deadline_deny":    "⏳ Auto-deny in %s",
+	"perm.deadline_allow":   "⏳ Auto-allow in %s",
+	"perm.deadline_pending": "⏳ Reminders stop in %s",
+	"perm.timeout_deny":     "⏰ No answer after %s: denied automatically",
+	"perm.timeout_allow":    "⏰ No answer after %s: allowed automatically",
+	"perm.timeout_pending":  "⏰ No answer after %s. Reminders stopped; the request is still pending.",
+	"perm.elsewhere":        "✅ Answered in another chat.",
+
+	// ExitPlanMode
+	"plan.title":     "📋 Plan ready for review",
+	"plan.empty":     "(empty plan)",
+	"plan.auto":      "✅ Approve & auto-accept edits",
+	"plan.manual":    "👍 Approve (manual approvals)",
+	"plan.keep":      "📝 Keep planning",
+	"plan.keep_hint": "📝 Reply to the plan with your feedback.",
+
+	"edit.summary":          "📝 +%d -%d",
+	"edit.approx":           " (line numbers relative to the edit)",
+	"edit.new_file.one":     "📄 New file, %d line",
+	"edit.new_file.other":   "📄 New file, %d lines",
+	"edit.more_lines.one":   "… %d more line in attached diff",
+	"edit.more_lines.other": "… %d more lines in attached diff",
+
+	// Questions
+	"question.title":        "❓ Question",
+	"question.multi":        " (multiple choice)",
+	"question.submit":       "📤 Submit",
+	"question.chat":         "💬 Chat about this",
+	"question.text_answer":  "✅ Text answer",
+	"question.voice_answer": "✅ Voice answer",
+	"question.chat_mode":    "💬 Chat mode selected",
+	"question.other":        "Other",
+	"question.other_prompt": "✏️ Send your answer to Q%d: %s\nThe next message you send within %d minutes is used.",
+	"question.other_saved":  "✅ Answer saved for Q%d. Press Submit when all questions are answered.",
+	"common.cancelled":      "❌ Cancelled",
+
+	// Callback toasts
+	"cb.page_expired":         "Page expired",
+	"cb.session_disconnected": "⚠️ Session disconnected",
+	"cb.expired_or_invalid":   "Expired or invalid",
+	"cb.decided":              "✅ %s",
+	"cb.invalid_data":         "Invalid data",
+	"cb.expired":              "Expired",
+	"cb.already_answered":     "Already answered",
+	"cb.pending_missing":      "Pending file not found",
+	"cb.question_expired":     "❌ Question expired",
+	"cb.save_failed":          "Failed to save answer",
+	"cb.chat_mode":            "Chat mode",
+	"cb.submitted":            "✅ Submitted",
+	"cb.invalid_question":     "Invalid question",
+	"cb.toggled":              "Toggled",
+	"cb.selected_done":        "✅ Selected",
+	"cb.selected":             "Selected",
+	"cb.no_target":            "No tmux target found",
+	"cb.inject_failed":        "❌ Injection failed",
+	"cb.resuming":             "✅ Resuming",
+	"cb.other_waiting":        "✏️ Send your answer",
+
+	// Errors and replies
+	"err.reply_to_target":    "💡 Please reply to a notification message to target a session.",
+	"err.multiple_sessions":  "❌ Multiple sessions bound to this group. Reply to a specific notification or pick one with /bot_use.",
+	"err.session_not_found":  "❌ tmux session not found.",
+	"err.session_ended":      "❌ tmux session not found. The Claude Code session may have ended.",
+	"err.no_target_in_msg":   "❌ No tmux session info found in the original message.",
+	"err.no_target":          "❌ No tmux session info found.",
+	"err.no_pane_in_reply":   "❌ No tmux session info (📟) found in the replied message.",
+	"err.inject_failed":      "❌ Injection failed: %v",
+	"err.send_failed":        "❌ Failed to send: %v",
+	"err.load_config":        "❌ Failed to load config: %v",
+	"err.save":               "❌ Failed to save: %v",
+	"err.read_pending":       "❌ Failed to read pending file.",
+	"err.not_paired":         "❌ Not paired. Use /bot_pair first.",
+	"err.generic":            "❌ %v",
+	"session.not_running":    "⚠️ Session is no longer running.",
+	"session.disconnected":   "⚠️ Session disconnected\n📟 %s\nTmux route auto-unbound.",
+	"session.route_kept":     "⚠️ Session disconnected\n📟 %s\nRoute kept as %s; it applies again when the session is back.",
+	"voice.failed_or_empty":  "❌ Transcription failed or empty.",
+	"voice.empty":            "❌ Transcription produced empty text.",
+	"resume.no_cwd":          "❌ No working directory info available for this session.",
+	"resume.none":            "📂 No previous sessions found for this project.",
+	"resume.none_other":      "📂 No other sessions found for this project.",
+	"time.minutes_ago":       "%dm ago",
+	"time.hours_ago":         "%dh ago",
+	"time.days_ago":          "%dd ago",
+	"permmode.detect_failed": "❌ Detect mode failed: %v",
+	"permmode.current":       "🔐 Current mode: %s",
+	"permmode.switch_failed": "❌ Switch failed: %v",
+	"permmode.switched":      "🔐 Switched to %s mode",
+	"capture.failed":         "❌ Capture failed: %v",
+	"capture.empty":          "(empty pane)",
+	"capture.truncated":      "...(truncated)",
+	"escape.failed":          "❌ Escape failed: %v",
+	"escape.sent":            "⏹ Escape sent",
+
+	// Pairing and status
+	"start.welcome":   "tg-cli bot is running. Use /bot_pair to pair this chat.",
+	"pair.already":    "Already paired.",
+	"pair.code":       "Pairing code: %s\n\nEnter this code in the bot terminal to approve.\n\nCode expires in 10 minutes.",
+	"status.unpaired": "Not paired. Use /bot_pair first.",
+	"status.ok":       "Bot is running and paired.",
+
+	// Routes and binding
+	"routes.none":         "No active route bindings.",
+	"routes.title":        "🗺 Route bindings:",
+	"routes.rules":        "🧭 Routing rules (first match wins):",
+	"routes.sessions":     "Sessions routed by a rule:",
+	"routes.matched":      "rule %s",
+	"routes.fanout":       "📣 Fan-out copies (every match applies):",
+	"routes.all_events":   "all events",
+	"bind.need_reply":     "❌ Reply to a notification message with /bot_bind to bind that session to this chat.",
+	"bind.empty_target":   "❌ Empty tmux target, cannot bind.",
+	"bind.choose":         "Choose binding type:\n%s\n📂 %s",
+	"bind.btn_tmux":       "📟 Pane",
+	"bind.btn_session":    "🔗 Session",
+	"bind.btn_project":    "📂 Project",
+	"bind.save_failed":    "❌ Failed to save binding: %v",
+	"bind.tmux_done":      "✅ Bound tmux pane to this chat.\n%s",
+	"bind.session_done":   "✅ Bound session to this chat, across resumes.\n%s",
+	"bind.project_done":   "✅ Bound project to this chat.\n📂 %s",
+	"unbind.need_reply":   "❌ Reply to a notification message with /bot_unbind to unbind that session.",
+	"unbind.tmux_done":    "✅ Unbound session.\n%s",
+	"unbind.confirm":      "Unbind project route?\n📂 %s\n⚠️ This affects all sessions in this project.",
+	"unbind.btn_yes":      "✅ Yes, unbind",
+	"unbind.btn_no":       "❌ Cancel",
+	"unbind.none":         "❌ No binding found for this session.",
+	"unbind.cancelled":    "❌ Unbind cancelled.",
+	"unbind.project_done": "✅ Unbound project route.\n📂 %s",
+	"alias.usage":         "Usage: /bot_alias <name>, replying to a session message. Routes bound to 🔗 Session then follow the alias.",
+	"alias.current":       "🏷 This session's alias: %s",
+	"alias.invalid":       "❌ Aliases are 1-32 letters, digits, '.', '_' or '-'.",
+	"alias.no_session":    "❌ No active Claude session in that pane.",
+	"alias.set":           "✅ Alias set: 🏷 %s\n📟 %s",
+
+	// Active session
+	"use.title":      "📌 Sessions bound to this group",
+	"use.hint":       "Tap a session to make it active for everyone here, or 👤 for just you. Plain messages and commands go to the active session.",
+	"use.clear":      "✖ Clear",
+	"use.cleared":    "Active session cleared",
+	"use.set_chat":   "Active for this chat: %s",
+	"use.set_me":     "Active for you: %s",
+	"use.none":       "❌ No sessions are bound to this group.",
+	"use.group_only": "❌ /bot_use only works in groups.",
+
+	// Group membership
+	"member.added":          "👋 Hi! Tap a running session to send its messages to this group, or reply /bot_bind to one of its notifications later.",
+	"member.added_unpaired": "👋 Hi! A paired user can route sessions here with /bot_bind.",
+	"member.no_sessions":    "No sessions are running right now.",
+	"member.bound":          "✅ Bound %s to this group",
+	"member.migrated.one":   "🔁 This group became a supergroup; moved %d route to it.",
+	"member.migrated.other": "🔁 This group became a supergroup; moved %d routes to it.",
+	"member.removed.one":    "🚪 The bot was removed from %[2]s; dropped %[1]d route that sent there.",
+	"member.removed.other":  "🚪 The bot was removed from %[2]s; dropped %[1]d routes that sent there.",
+
+	// Language
+	"lang.current": "🌐 Language: %s\nAvailable: %s\n\nUse /bot_lang <code> to change it, or /bot_lang auto to follow Telegram.",
+	"lang.set":     "🌐 Language set to %s.",
+	"lang.auto":    "🌐 Language now follows your Telegram settings.",
+	"lang.unknown": "❌ Unknown language: %s",
+
+	// /bot_usage
+	"usage.title":          "📊 Usage: %s",
+	"usage.report_title":   "📊 Daily usage report",
+	"usage.today":          "Today",
+	"usage.week":           "This week",
+	"usage.month":          "This month",
+	"usage.total":          "%s tokens (in %s · out %s · cache %s)",
+	"usage.cost":           "💵 $%.2f",
+	"usage.sessions.one":   "%d session",
+	"usage.sessions.other": "%d sessions",
+	"usage.project":        "• %s: %s tokens",
+	"usage.more.one":       "… %d more project",
+	"usage.more.other":     "… %d more projects",
+	"usage.none":           "No usage recorded.",
+	"usage.help":           "Usage: /bot_usage [today|week|month]",
+
+	// Pinned chat overview
+	"overview.title.one":    "📌 %d session",
+	"overview.title.other":  "📌 %d sessions",
+	"overview.none":         "No active sessions.",
+	"overview.running":      "running",
+	"overview.idle":         "idle",
+	"overview.mode.default": "default",
+	"overview.mode.plan":    "plan",
+	"overview.mode.auto":    "auto-edit",
+	"overview.mode.bypass":  "bypass",
+	"overview.now":          "now",
+	"overview.minutes":      "%dm",
+	"overview.hours":        "%dh",
+	"overview.days":         "%dd",
+
+	// End-of-session report
+	"report.duration":         "⏱ %s",
+	"report.turns.one":        "💬 %d turn",
+	"report.turns.other":      "💬 %d turns",
+	"report.files":            "📝 Files changed (%d):",
+	"report.more_files.one":   "… %d more file",
+	"report.more_files.other": "… %d more files",
+	"report.git_since":        "📊 Git since %s:",
+	"report.git_uncommitted":  "📊 Uncommitted changes:",
+	"report.git_clean":        "📊 Git: no changes",
+	"report.diff":             "📄 Full diff",
+	"report.resume":           "▶️ Resume",
+	"report.no_diff":          "No changes",
+	"report.diff_failed":      "❌ Failed to get diff",
+	"report.diff_caption":     "📄 %s: git diff %s",
+
+	// /bot_git
+	"git.help":     "Usage: /bot_git status | diff [--staged] [rev] [-- path] | log [n] | show <rev>\nReply to a notification, or send it in a group bound to one session.",
+	"git.title":    "🔀 git %s · %s",
+	"git.bad_arg":  "❌ Option not allowed: %s",
+	"git.no_cwd":   "❌ No working directory found for this session.",
+	"git.failed":   "❌ git %s failed: %s",
+	"git.empty":    "(no output)",
+	"git.binary":   "Binary file changed",
+	"git.too_long": "📎 Output too long, sent as a file.",
+
+	// /bot_rules
+	"rules.title":         "🛡 Permission rules · %s",
+	"rules.scope.user":    "User",
+	"rules.scope.project": "Project",
+	"rules.scope.local":   "Local",
+	"rules.empty":         "(none)",
+	"rules.too_many":      "Buttons are shown for the first %d rules only.",
+	"rules.read_failed":   "❌ Failed to read settings: %v",
+	"rules.removed":       "🗑 Removed %s",
+	"rules.moved":         "✅ Moved %s to %s",
+	"rules.changed":       "⚠️ Settings changed, list refreshed",
+
+	// /bot_pending
+	"pending.title.one":             "⏳ %d unanswered request",
+	"pending.title.other":           "⏳ %d unanswered requests",
+	"pending.none":                  "✅ No unanswered requests.",
+	"pending.question":              "Question",
+	"pending.plan":                  "Plan approval",
+	"pending.approve_session.one":   "✅ Approve %d · %d request",
+	"pending.approve_session.other": "✅ Approve %d · %d requests",
+	"pending.deny_all":              "⛔ Deny all",
+	"pending.done_allow.one":        "✅ Approved %d request",
+	"pending.done_allow.other":      "✅ Approved %d requests",
+	"pending.done_deny.one":         "⛔ Denied %d request",
+	"pending.done_deny.other":       "⛔ Denied %d requests",
+}
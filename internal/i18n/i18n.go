@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Default is the language used when nothing is configured or detected.
+const Default = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"zh": zh,
+}
+
+// pluralRules picks the plural form suffix for a count in each language.
+var pluralRules = map[string]func(n int) string{
+	"en": func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"zh": func(int) string { return "other" },
+}
+
+// Normalize maps a Telegram language_code such as "en-US" or "zh-hans" to a
+// supported language, or "" if none matches.
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i != -1 {
+		code = code[:i]
+	}
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// Languages returns the supported language codes, sorted.
+func Languages() []string {
+	var langs []string
+	for l := range catalogs {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T returns the message for key in lang, formatted with args. Missing
+// entries fall back to English, then to the key itself.
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N is T for messages that depend on a count. It looks up key+".one",
+// key+".other", etc. per the language's plural rules; n is passed as the
+// first format argument.
+func N(lang, key string, n int, args ...interface{}) string {
+	if _, ok := catalogs[lang]; !ok {
+		lang = Default
+	}
+	form := pluralRules[lang](n)
+	args = append([]interface{}{n}, args...)
+	if _, ok := catalogs[lang][key+"."+form]; ok {
+		return T(lang, key+"."+form, args...)
+	}
+	return T(lang, key+".other", args...)
+}
@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalogsCoverEnglishKeys(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key := range en {
+			if _, ok := catalog[key]; ok {
+				continue
+			}
+			// Languages without a "one" form only need "other"
+			if base, ok := strings.CutSuffix(key, ".one"); ok {
+				if _, ok := catalog[base+".other"]; ok {
+					continue
+				}
+			}
+			t.Errorf("%s catalog missing key %q", lang, key)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"en", "en"},
+		{"en-US", "en"},
+		{"zh-hans", "zh"},
+		{"ZH_TW", "zh"},
+		{"fr", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.expect {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.expect)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	if got := N("en", "edit.new_file", 1); got != "📄 New file, 1 line" {
+		t.Errorf("N(en, 1) = %q", got)
+	}
+	if got := N("en", "edit.new_file", 3); got != "📄 New file, 3 lines" {
+		t.Errorf("N(en, 3) = %q", got)
+	}
+	if got := N("zh", "edit.new_file", 1); got != "📄 新文件, 共 1 行" {
+		t.Errorf("N(zh, 1) = %q", got)
+	}
+	if got := T("xx", "perm.allow"); got != "Allow" {
+		t.Errorf("T(unknown lang) = %q, want English fallback", got)
+	}
+}
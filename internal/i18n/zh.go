@@ -0,0 +1,275 @@
+package i18n
+
+var zh = map[string]string{
+	// Notifications
+	"notify.session_started":      "🟢 会话已开始",
+	"notify.session_ended":        "🔴 会话已结束",
+	"notify.update":               "💬 进展",
+	"notify.task_completed":       "✅ 任务完成",
+	"notify.attention":            "🔔 需要关注",
+	"notify.subagent_done":        "🤖 子代理已完成",
+	"notify.compact_auto":         "🗜️ 正在自动压缩上下文",
+	"notify.compact_manual":       "🗜️ 正在压缩上下文",
+	"notify.tool_done":            "🔧 %s 已完成",
+	"notify.tool_failed":          "⚠️ %s 失败",
+	"notify.compact_instructions": "压缩说明:",
+	"notify.project":              "项目: %s",
+	"notify.context":              "📊 上下文: %d%% (%s/%s)",
+	"notify.claude":               "💬 Claude:",
+	"notify.you":                  "👤 你: %s",
+
+	// TodoWrite checklist
+	"todo.title": "📋 任务: 已完成 %d/%d",
+	"todo.empty": "(暂无任务)",
+
+	// Context window alerts
+	"context.alert":   "⚠️ 上下文已用 %d%%",
+	"context.hint":    "可压缩或清空对话, 也可回复一个重点来执行 /compact <重点>。",
+	"context.compact": "🗜 /compact",
+	"context.clear":   "🧹 /clear",
+	"context.sent":    "✅ 已发送 %s",
+
+	// Permission requests
+	"perm.title":            "🔐 权限请求",
+	"perm.tool":             "🔧 工具: %s",
+	"perm.mcp":              "🔌 MCP: %s",
+	"perm.allow":            "允许",
+	"perm.deny":             "拒绝",
+	"perm.always_allow":     "始终允许",
+	"perm.allow_dir":        "允许目录: %s",
+	"perm.edit":             "编辑",
+	"perm.edit_prompt":      "✏️ 回复此消息以提供新的 %s。当前值:",
+	"perm.edit_expired":     "❌ 该权限请求已不再等待处理。",
+	"perm.edited":           "✏️ 已按修改后的 %s 允许",
+	"perm.edit_original":    "原始值:",
+	"perm.edit_new":         "修改后:",
+	"perm.reminder":         "⏰ 权限请求已等待 %s",
+	"perm.deadline_deny":    "⏳ %s 后自动拒绝",
+	"perm.deadline_allow":   "⏳ %s 后自动允许",
+	"perm.deadline_pending": "⏳ %s 后停止提醒",
+	"perm.timeout_deny":     "⏰ %s 无人响应：已自动拒绝",
+	"perm.timeout_allow":    "⏰ %s 无人响应：已自动允许",
+	"perm.timeout_pending":  "⏰ %s 无人响应。已停止提醒，请求仍在等待。",
+	"perm.elsewhere":        "✅ 已在其他聊天中回答。",
+
+	// ExitPlanMode
+	"plan.title":     "📋 计划待审阅",
+	"plan.empty":     "(空计划)",
+	"plan.auto":      "✅ 批准并自动接受编辑",
+	"plan.manual":    "👍 批准(手动审批)",
+	"plan.keep":      "📝 继续规划",
+	"plan.keep_hint": "📝 请回复该计划提供你的反馈。",
+
+	"edit.summary":          "📝 +%d -%d",
+	"edit.approx":           " (行号相对于修改片段)",
+	"edit.new_file.other":   "📄 新文件, 共 %d 行",
+	"edit.more_lines.other": "… 还有 %d 行, 见附件 diff",
+
+	// Questions
+	"question.title":        "❓ 问题",
+	"question.multi":        " (多选)",
+	"question.submit":       "📤 提交",
+	"question.chat":         "💬 聊聊这个问题",
+	"question.text_answer":  "✅ 文字回答",
+	"question.voice_answer": "✅ 语音回答",
+	"question.chat_mode":    "💬 已选择对话模式",
+	"question.other":        "其他",
+	"question.other_prompt": "✏️ 请发送 Q%d 的回答: %s\n将使用你在 %d 分钟内发送的下一条消息。",
+	"question.other_saved":  "✅ 已保存 Q%d 的回答。所有问题回答完毕后请点击提交。",
+	"common.cancelled":      "❌ 已取消",
+
+	// Callback toasts
+	"cb.page_expired":         "页面已过期",
+	"cb.session_disconnected": "⚠️ 会话已断开",
+	"cb.expired_or_invalid":   "已过期或无效",
+	"cb.decided":              "✅ %s",
+	"cb.invalid_data":         "无效数据",
+	"cb.expired":              "已过期",
+	"cb.already_answered":     "已回答",
+	"cb.pending_missing":      "未找到待处理文件",
+	"cb.question_expired":     "❌ 问题已过期",
+	"cb.save_failed":          "保存回答失败",
+	"cb.chat_mode":            "对话模式",
+	"cb.submitted":            "✅ 已提交",
+	"cb.invalid_question":     "无效问题",
+	"cb.toggled":              "已切换",
+	"cb.selected_done":        "✅ 已选择",
+	"cb.selected":             "已选择",
+	"cb.no_target":            "未找到 tmux 目标",
+	"cb.inject_failed":        "❌ 注入失败",
+	"cb.resuming":             "✅ 正在恢复",
+	"cb.other_waiting":        "✏️ 请发送你的回答",
+
+	// Errors and replies
+	"err.reply_to_target":    "💡 请回复一条通知消息以指定会话。",
+	"err.multiple_sessions":  "❌ 此群组绑定了多个会话, 请回复具体的通知消息, 或用 /bot_use 选择一个。",
+	"err.session_not_found":  "❌ 未找到 tmux 会话。",
+	"err.session_ended":      "❌ 未找到 tmux 会话, Claude Code 会话可能已结束。",
+	"err.no_target_in_msg":   "❌ 原消息中没有 tmux 会话信息。",
+	"err.no_target":          "❌ 未找到 tmux 会话信息。",
+	"err.no_pane_in_reply":   "❌ 被回复的消息中没有 tmux 会话信息 (📟)。",
+	"err.inject_failed":      "❌ 注入失败: %v",
+	"err.send_failed":        "❌ 发送失败: %v",
+	"err.load_config":        "❌ 加载配置失败: %v",
+	"err.save":               "❌ 保存失败: %v",
+	"err.read_pending":       "❌ 读取待处理文件失败。",
+	"err.not_paired":         "❌ 尚未配对, 请先使用 /bot_pair。",
+	"err.generic":            "❌ %v",
+	"session.not_running":    "⚠️ 会话已不在运行。",
+	"session.disconnected":   "⚠️ 会话已断开\n📟 %s\ntmux 路由已自动解绑。",
+	"session.route_kept":     "⚠️ 会话已断开\n📟 %s\n路由已保留为 %s,会话恢复后重新生效。",
+	"voice.failed_or_empty":  "❌ 语音识别失败或结果为空。",
+	"voice.empty":            "❌ 语音识别结果为空。",
+	"resume.no_cwd":          "❌ 此会话没有可用的工作目录信息。",
+	"resume.none":            "📂 此项目没有历史会话。",
+	"resume.none_other":      "📂 此项目没有其他会话。",
+	"time.minutes_ago":       "%d 分钟前",
+	"time.hours_ago":         "%d 小时前",
+	"time.days_ago":          "%d 天前",
+	"permmode.detect_failed": "❌ 检测模式失败: %v",
+	"permmode.current":       "🔐 当前模式: %s",
+	"permmode.switch_failed": "❌ 切换失败: %v",
+	"permmode.switched":      "🔐 已切换到 %s 模式",
+	"capture.failed":         "❌ 截取失败: %v",
+	"capture.empty":          "(空窗格)",
+	"capture.truncated":      "...(已截断)",
+	"escape.failed":          "❌ 发送 Escape 失败: %v",
+	"escape.sent":            "⏹ 已发送 Escape",
+
+	// Pairing and status
+	"start.welcome":   "tg-cli 机器人正在运行。使用 /bot_pair 配对此聊天。",
+	"pair.already":    "已配对。",
+	"pair.code":       "配对码: %s\n\n请在机器人终端输入此配对码以批准。\n\n配对码 10 分钟后过期。",
+	"status.unpaired": "尚未配对, 请先使用 /bot_pair。",
+	"status.ok":       "机器人正在运行且已配对。",
+
+	// Routes and binding
+	"routes.none":         "没有路由绑定。",
+	"routes.title":        "🗺 路由绑定:",
+	"routes.rules":        "🧭 路由规则(按顺序匹配第一条):",
+	"routes.sessions":     "按规则路由的会话:",
+	"routes.matched":      "规则 %s",
+	"routes.fanout":       "📣 抄送(所有匹配项均生效):",
+	"routes.all_events":   "全部事件",
+	"bind.need_reply":     "❌ 请用 /bot_bind 回复一条通知消息, 将该会话绑定到此聊天。",
+	"bind.empty_target":   "❌ tmux 目标为空, 无法绑定。",
+	"bind.choose":         "选择绑定类型:\n%s\n📂 %s",
+	"bind.btn_tmux":       "📟 窗格",
+	"bind.btn_session":    "🔗 会话",
+	"bind.btn_project":    "📂 项目",
+	"bind.save_failed":    "❌ 保存绑定失败: %v",
+	"bind.tmux_done":      "✅ 已将 tmux 窗格绑定到此聊天。\n%s",
+	"bind.session_done":   "✅ 已将会话绑定到此聊天, 恢复会话后仍然有效。\n%s",
+	"bind.project_done":   "✅ 已将项目绑定到此聊天。\n📂 %s",
+	"unbind.need_reply":   "❌ 请用 /bot_unbind 回复一条通知消息以解绑该会话。",
+	"unbind.tmux_done":    "✅ 已解绑会话。\n%s",
+	"unbind.confirm":      "解绑项目路由?\n📂 %s\n⚠️ 这会影响此项目下的所有会话。",
+	"unbind.btn_yes":      "✅ 确认解绑",
+	"unbind.btn_no":       "❌ 取消",
+	"unbind.none":         "❌ 此会话没有绑定。",
+	"unbind.cancelled":    "❌ 已取消解绑。",
+	"unbind.project_done": "✅ 已解绑项目路由。\n📂 %s",
+	"alias.usage":         "用法: 回复会话消息发送 /bot_alias <名称>。绑定为 🔗 会话 的路由会跟随别名。",
+	"alias.current":       "🏷 此会话的别名: %s",
+	"alias.invalid":       "❌ 别名为 1-32 个字母、数字、'.'、'_' 或 '-'。",
+	"alias.no_session":    "❌ 该窗格中没有活动的 Claude 会话。",
+	"alias.set":           "✅ 已设置别名: 🏷 %s\n📟 %s",
+
+	// Active session
+	"use.title":      "📌 此群组绑定的会话",
+	"use.hint":       "点击会话设为本群的当前会话, 点击 👤 仅对你生效。普通消息和命令会发送到当前会话。",
+	"use.clear":      "✖ 清除",
+	"use.cleared":    "已清除当前会话",
+	"use.set_chat":   "本群当前会话: %s",
+	"use.set_me":     "你的当前会话: %s",
+	"use.none":       "❌ 此群组没有绑定会话。",
+	"use.group_only": "❌ /bot_use 仅在群组中可用。",
+
+	// Group membership
+	"member.added":          "👋 你好!点击正在运行的会话, 将其消息发送到此群组; 之后也可以用 /bot_bind 回复会话通知来绑定。",
+	"member.added_unpaired": "👋 你好!已配对的用户可以用 /bot_bind 将会话路由到这里。",
+	"member.no_sessions":    "当前没有正在运行的会话。",
+	"member.bound":          "✅ 已将 %s 绑定到此群组",
+	"member.migrated.other": "🔁 此群组已升级为超级群组, 已迁移 %d 条路由。",
+	"member.removed.other":  "🚪 机器人已被移出 %[2]s, 已删除指向该群的 %[1]d 条路由。",
+
+	// Language
+	"lang.current": "🌐 语言: %s\n可选: %s\n\n使用 /bot_lang <代码> 切换, 或 /bot_lang auto 跟随 Telegram 设置。",
+	"lang.set":     "🌐 语言已设置为 %s。",
+	"lang.auto":    "🌐 语言将跟随你的 Telegram 设置。",
+	"lang.unknown": "❌ 未知语言: %s",
+
+	// /bot_usage
+	"usage.title":          "📊 用量: %s",
+	"usage.report_title":   "📊 每日用量报告",
+	"usage.today":          "今天",
+	"usage.week":           "本周",
+	"usage.month":          "本月",
+	"usage.total":          "%s tokens (输入 %s · 输出 %s · 缓存 %s)",
+	"usage.cost":           "💵 $%.2f",
+	"usage.sessions.other": "%d 个会话",
+	"usage.project":        "• %s: %s tokens",
+	"usage.more.other":     "… 另有 %d 个项目",
+	"usage.none":           "暂无用量记录。",
+	"usage.help":           "用法: /bot_usage [today|week|month]",
+
+	// Pinned chat overview
+	"overview.title.other":  "📌 %d 个会话",
+	"overview.none":         "暂无活动会话。",
+	"overview.running":      "运行中",
+	"overview.idle":         "空闲",
+	"overview.mode.default": "默认",
+	"overview.mode.plan":    "计划",
+	"overview.mode.auto":    "自动编辑",
+	"overview.mode.bypass":  "绕过",
+	"overview.now":          "刚刚",
+	"overview.minutes":      "%d分钟",
+	"overview.hours":        "%d小时",
+	"overview.days":         "%d天",
+
+	// End-of-session report
+	"report.duration":         "⏱ %s",
+	"report.turns.other":      "💬 %d 轮对话",
+	"report.files":            "📝 修改的文件 (%d):",
+	"report.more_files.other": "… 还有 %d 个文件",
+	"report.git_since":        "📊 自 %s 以来的 Git 变更:",
+	"report.git_uncommitted":  "📊 未提交的变更:",
+	"report.git_clean":        "📊 Git: 无变更",
+	"report.diff":             "📄 完整 diff",
+	"report.resume":           "▶️ 恢复会话",
+	"report.no_diff":          "无变更",
+	"report.diff_failed":      "❌ 获取 diff 失败",
+	"report.diff_caption":     "📄 %s: git diff %s",
+
+	// /bot_git
+	"git.help":     "用法: /bot_git status | diff [--staged] [rev] [-- path] | log [n] | show <rev>\n回复一条通知，或在绑定了单个会话的群组中发送。",
+	"git.title":    "🔀 git %s · %s",
+	"git.bad_arg":  "❌ 不允许的选项: %s",
+	"git.no_cwd":   "❌ 未找到该会话的工作目录。",
+	"git.failed":   "❌ git %s 失败: %s",
+	"git.empty":    "(无输出)",
+	"git.binary":   "二进制文件已修改",
+	"git.too_long": "📎 输出过长，已作为文件发送。",
+
+	// /bot_rules
+	"rules.title":         "🛡 权限规则 · %s",
+	"rules.scope.user":    "用户",
+	"rules.scope.project": "项目",
+	"rules.scope.local":   "本地",
+	"rules.empty":         "(无)",
+	"rules.too_many":      "仅为前 %d 条规则显示按钮。",
+	"rules.read_failed":   "❌ 读取设置失败: %v",
+	"rules.removed":       "🗑 已删除 %s",
+	"rules.moved":         "✅ 已将 %s 移至%s",
+	"rules.changed":       "⚠️ 设置已变更，列表已刷新",
+
+	// /bot_pending
+	"pending.title.other":           "⏳ %d 个未处理请求",
+	"pending.none":                  "✅ 没有未处理的请求。",
+	"pending.question":              "问题",
+	"pending.plan":                  "计划审批",
+	"pending.approve_session.other": "✅ 批准 %d · %d 个请求",
+	"pending.deny_all":              "⛔ 全部拒绝",
+	"pending.done_allow.other":      "✅ 已批准 %d 个请求",
+	"pending.done_deny.other":       "⛔ 已拒绝 %d 个请求",
+}
@@ -63,6 +63,8 @@ func InjectText(target TmuxTarget, text string) error {
 		return fmt.Errorf("paste-buffer failed: %w", err)
 	}
 	time.Sleep(1000 * time.Millisecond)
+	// Remember the prompt before submitting, the prompt hook may fire first
+	recordInjection(target.PaneID, text, time.Now())
 	// Submit
 	if err := tmuxCmd(target, "send-keys", "-t", target.PaneID, "C-m").Run(); err != nil {
 		return fmt.Errorf("submit failed: %w", err)
@@ -115,3 +117,63 @@ func GetPaneTitle(target TmuxTarget) (string, error) {
 	}
 	return strings.TrimSpace(string(out)), nil
 }
+
+// GetPaneCommand returns the command running in the foreground of the pane.
+func GetPaneCommand(target TmuxTarget) (string, error) {
+	cmd := tmuxCmd(target, "display-message", "-p", "-t", target.PaneID, "#{pane_current_command}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetPanePath returns the current working directory of the pane.
+func GetPanePath(target TmuxTarget) (string, error) {
+	cmd := tmuxCmd(target, "display-message", "-p", "-t", target.PaneID, "#{pane_current_path}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetPaneNames returns the names of the tmux session and window holding the pane.
+func GetPaneNames(target TmuxTarget) (session, window string, err error) {
+	cmd := tmuxCmd(target, "display-message", "-p", "-t", target.PaneID, "#{session_name}\t#{window_name}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	session, window, _ = strings.Cut(strings.TrimRight(string(out), "\n"), "\t")
+	return session, window, nil
+}
+
+// GetPaneLocation returns the pane's "session:window.pane" location, which
+// unlike the pane ID stays the same when tmux restarts with the same layout.
+func GetPaneLocation(target TmuxTarget) (string, error) {
+	cmd := tmuxCmd(target, "display-message", "-p", "-t", target.PaneID, "#{session_name}:#{window_index}.#{pane_index}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// FindPaneByLocation returns the pane currently at a "session:window.pane"
+// location on socket.
+func FindPaneByLocation(location, socket string) (TmuxTarget, bool) {
+	target := TmuxTarget{Socket: socket}
+	out, err := tmuxCmd(target, "list-panes", "-a", "-F", "#{pane_id}\t#{session_name}:#{window_index}.#{pane_index}").Output()
+	if err != nil {
+		return TmuxTarget{}, false
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		paneID, loc, ok := strings.Cut(line, "\t")
+		if ok && loc == location {
+			target.PaneID = paneID
+			return target, true
+		}
+	}
+	return TmuxTarget{}, false
+}
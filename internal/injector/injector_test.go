@@ -184,3 +184,21 @@ func TestCapturePane(t *testing.T) {
 		t.Error("CapturePane should fail on non-existent pane")
 	}
 }
+
+func TestTakeInjection(t *testing.T) {
+	now := time.Now()
+	recordInjection("%90", "fix the tests\r\n", now.Add(-time.Minute))
+	recordInjection("%90", "old", now.Add(-InjectionTTL-time.Second))
+	if takeInjection("%91", "fix the tests", now) {
+		t.Error("matched an injection into another pane")
+	}
+	if !takeInjection("%90", "  fix the tests", now) {
+		t.Error("missed a recent injection")
+	}
+	if takeInjection("%90", "fix the tests", now) {
+		t.Error("matched the same injection twice")
+	}
+	if takeInjection("%90", "old", now) {
+		t.Error("matched an expired injection")
+	}
+}
@@ -0,0 +1,62 @@
+package injector
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// InjectionTTL is how long injected text can still be matched to a prompt
+// Claude Code submits. Prompts sent while Claude is busy are queued and only
+// submitted when it gets to them.
+const InjectionTTL = 10 * time.Minute
+
+type injection struct {
+	text string
+	at   time.Time
+}
+
+// injections remembers recent InjectText calls per pane ID.
+var injections = struct {
+	sync.Mutex
+	byPane map[string][]injection
+}{byPane: make(map[string][]injection)}
+
+func promptKey(text string) string {
+	return strings.TrimSpace(NormalizeText(text))
+}
+
+// recordInjection remembers text injected into a pane and forgets
+// injections older than InjectionTTL.
+func recordInjection(paneID, text string, now time.Time) {
+	injections.Lock()
+	defer injections.Unlock()
+	var kept []injection
+	for _, in := range injections.byPane[paneID] {
+		if now.Sub(in.at) < InjectionTTL {
+			kept = append(kept, in)
+		}
+	}
+	injections.byPane[paneID] = append(kept, injection{text: promptKey(text), at: now})
+}
+
+// TakeInjection reports whether text was injected into the pane within
+// InjectionTTL and forgets that injection, so the same text typed at the
+// terminal later is not mistaken for it.
+func TakeInjection(paneID, text string) bool {
+	return takeInjection(paneID, text, time.Now())
+}
+
+func takeInjection(paneID, text string, now time.Time) bool {
+	injections.Lock()
+	defer injections.Unlock()
+	key := promptKey(text)
+	list := injections.byPane[paneID]
+	for i, in := range list {
+		if in.text == key && now.Sub(in.at) < InjectionTTL {
+			injections.byPane[paneID] = append(list[:i:i], list[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/diff"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// MaxEditPreviewLines caps the diff lines shown in a permission message;
+// the full diff is sent as an attachment beyond that.
+const MaxEditPreviewLines = 150
+
+const editContextLines = 3
+
+// EditPreview is the rendered change of an Edit, MultiEdit or Write call.
+type EditPreview struct {
+	FilePath  string
+	NewFile   bool
+	Approx    bool   // file unreadable or edit didn't apply; line numbers are relative
+	Body      string // line-numbered hunks for the message
+	Unified   string // standard unified diff for attachments
+	Added     int
+	Removed   int
+	Truncated bool // Body was cut at MaxEditPreviewLines
+	Omitted   int  // diff lines left out of Body
+}
+
+// BuildEditPreview computes the diff a file-editing tool call would produce
+// against the file currently on disk. ok is false for other tools.
+func BuildEditPreview(toolName string, toolInput map[string]interface{}) (EditPreview, bool) {
+	path, _ := toolInput["file_path"].(string)
+	p := EditPreview{FilePath: path}
+	var oldText, newText string
+	current, readErr := os.ReadFile(path)
+	switch toolName {
+	case "Edit":
+		edit := editOp{
+			old: stringField(toolInput, "old_string"),
+			new: stringField(toolInput, "new_string"),
+		}
+		edit.all, _ = toolInput["replace_all"].(bool)
+		oldText, newText, p.Approx = applyEdits(current, readErr, []editOp{edit})
+	case "MultiEdit":
+		var edits []editOp
+		list, _ := toolInput["edits"].([]interface{})
+		for _, e := range list {
+			m, _ := e.(map[string]interface{})
+			op := editOp{old: stringField(m, "old_string"), new: stringField(m, "new_string")}
+			op.all, _ = m["replace_all"].(bool)
+			edits = append(edits, op)
+		}
+		oldText, newText, p.Approx = applyEdits(current, readErr, edits)
+	case "Write":
+		newText = stringField(toolInput, "content")
+		if readErr != nil {
+			p.NewFile = true
+		} else {
+			oldText = string(current)
+		}
+	default:
+		return p, false
+	}
+	lines := diff.Lines(diff.SplitLines(oldText), diff.SplitLines(newText))
+	p.Added, p.Removed = diff.Stats(lines)
+	oldName, newName := "a/"+strings.TrimPrefix(path, "/"), "b/"+strings.TrimPrefix(path, "/")
+	if p.NewFile {
+		oldName = "/dev/null"
+	}
+	p.Unified = diff.Unified(oldName, newName, oldText, newText, editContextLines)
+	if p.NewFile {
+		setPreviewBody(&p, []diff.Hunk{{Lines: lines}})
+	} else {
+		setPreviewBody(&p, diff.Hunks(lines, editContextLines))
+	}
+	return p, true
+}
+
+// setPreviewBody renders hunks as line-numbered Body lines, capped at
+// MaxEditPreviewLines. New files are shown without hunk headers.
+func setPreviewBody(p *EditPreview, hunks []diff.Hunk) {
+	var body []string
+	for _, h := range hunks {
+		if p.NewFile {
+			for _, l := range h.Lines {
+				body = append(body, fmt.Sprintf("%4d + %s", l.NewNum, l.Text))
+			}
+			continue
+		}
+		body = append(body, h.Header())
+		for _, l := range h.Lines {
+			body = append(body, formatDiffLine(l))
+		}
+	}
+	if len(body) > MaxEditPreviewLines {
+		p.Omitted = len(body) - MaxEditPreviewLines
+		body = body[:MaxEditPreviewLines]
+		p.Truncated = true
+	}
+	p.Body = strings.Join(body, "\n")
+}
+
+// formatDiffLine prefixes a diff line with its line number: the old number
+// for removed lines, the new number otherwise.
+func formatDiffLine(l diff.Line) string {
+	switch l.Kind {
+	case diff.Delete:
+		return fmt.Sprintf("%4d - %s", l.OldNum, l.Text)
+	case diff.Insert:
+		return fmt.Sprintf("%4d + %s", l.NewNum, l.Text)
+	}
+	return fmt.Sprintf("%4d   %s", l.NewNum, l.Text)
+}
+
+type editOp struct {
+	old, new string
+	all      bool
+}
+
+// applyEdits applies edits to the file content. When the file can't be read
+// or an edit doesn't match, it falls back to diffing the edit strings alone.
+func applyEdits(current []byte, readErr error, edits []editOp) (string, string, bool) {
+	if readErr == nil {
+		content := string(current)
+		applied := true
+		for _, e := range edits {
+			if e.old == "" || !strings.Contains(content, e.old) {
+				applied = false
+				break
+			}
+			if e.all {
+				content = strings.ReplaceAll(content, e.old, e.new)
+			} else {
+				content = strings.Replace(content, e.old, e.new, 1)
+			}
+		}
+		if applied {
+			return string(current), content, false
+		}
+	}
+	var olds, news []string
+	for _, e := range edits {
+		olds = append(olds, e.old)
+		news = append(news, e.new)
+	}
+	return strings.Join(olds, "\n"), strings.Join(news, "\n"), true
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// buildEditLines renders the permission body for a file-editing tool.
+func buildEditLines(p *EditPreview, lang string) []string {
+	lines := []string{"file_path: " + p.FilePath}
+	summary := i18n.T(lang, "edit.summary", p.Added, p.Removed)
+	if p.NewFile {
+		summary = i18n.N(lang, "edit.new_file", p.Added)
+	} else if p.Approx {
+		summary += i18n.T(lang, "edit.approx")
+	}
+	lines = append(lines, summary)
+	if p.Body != "" {
+		body := p.Body
+		if p.Omitted > 0 {
+			body += "\n" + i18n.N(lang, "edit.more_lines", p.Omitted)
+		}
+		lines = append(lines, "```diff\n"+body+"\n```")
+	}
+	return lines
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/diff"
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// GitFilePreview converts one file of a git diff into the preview shown for
+// Edit permission requests.
+func GitFilePreview(f diff.FileDiff) EditPreview {
+	p := EditPreview{FilePath: f.Path(), NewFile: f.NewFile, Unified: f.Raw}
+	for _, h := range f.Hunks {
+		added, removed := diff.Stats(h.Lines)
+		p.Added += added
+		p.Removed += removed
+	}
+	setPreviewBody(&p, f.Hunks)
+	return p
+}
+
+// BuildGitDiffBlocks renders every file of a unified git diff as its own
+// block, formatted like an Edit permission body. truncated reports whether
+// any block was cut at MaxEditPreviewLines.
+func BuildGitDiffBlocks(unified, lang string) (blocks []string, truncated bool) {
+	for _, f := range diff.ParseUnified(unified) {
+		if f.Binary {
+			blocks = append(blocks, "file_path: "+f.Path()+"\n"+i18n.T(lang, "git.binary"))
+			continue
+		}
+		p := GitFilePreview(f)
+		truncated = truncated || p.Truncated
+		blocks = append(blocks, strings.Join(buildEditLines(&p, lang), "\n"))
+	}
+	return blocks, truncated
+}
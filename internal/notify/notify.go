@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
 )
 
 type NotificationData struct {
 	Event             string
+	Subject           string // tool name (PostToolUse), subagent type (SubagentStop) or compaction trigger (PreCompact)
+	Failed            bool   // PostToolUse: the tool reported an error
 	Project           string
 	CWD               string
 	Body              string
@@ -17,6 +21,7 @@ type NotificationData struct {
 	ContextUsedPct    int // -1 means no data
 	ContextWindowSize int
 	ContextUsedTokens int
+	Lang              string
 }
 
 type PermissionData struct {
@@ -25,6 +30,8 @@ type PermissionData struct {
 	TmuxTarget string
 	ToolName   string
 	ToolInput  map[string]interface{}
+	Edit       *EditPreview // set for Edit, MultiEdit and Write
+	Lang       string
 }
 
 type QuestionOption struct {
@@ -47,6 +54,7 @@ type QuestionData struct {
 	Question   string
 	Options    []QuestionOption
 	Questions  []QuestionEntry
+	Lang       string
 }
 
 // CompressPath shortens a filesystem path by abbreviating intermediate components to their first character.
@@ -76,7 +84,8 @@ func FormatPaneID(tmuxTarget string) string {
 	return tmuxTarget
 }
 
-func formatTokens(v float64) string {
+// FormatTokens renders a token count as e.g. "45.2k" or "1.3M".
+func FormatTokens(v float64) string {
 	if v >= 1_000_000 {
 		return fmt.Sprintf("%.1fM", v/1_000_000)
 	}
@@ -92,44 +101,71 @@ func projectDisplay(project, cwd string) string {
 }
 
 func BuildNotificationText(data NotificationData) string {
-	var emoji, status string
+	var statusLine string
 	switch {
 	case data.Event == "SessionStart":
-		emoji = "🟢"
-		status = "Session Started"
+		statusLine = i18n.T(data.Lang, "notify.session_started")
 	case data.Event == "SessionEnd":
-		emoji = "🔴"
-		status = "Session Ended"
+		statusLine = i18n.T(data.Lang, "notify.session_ended")
 	case data.Event == "PreToolUse":
-		emoji = "💬"
-		status = "Update"
+		statusLine = i18n.T(data.Lang, "notify.update")
+	case data.Event == "Notification":
+		statusLine = i18n.T(data.Lang, "notify.attention")
+	case data.Event == "SubagentStop":
+		statusLine = i18n.T(data.Lang, "notify.subagent_done")
+		if data.Subject != "" {
+			statusLine += ": " + data.Subject
+		}
+	case data.Event == "PreCompact" && data.Subject == "manual":
+		statusLine = i18n.T(data.Lang, "notify.compact_manual")
+	case data.Event == "PreCompact":
+		statusLine = i18n.T(data.Lang, "notify.compact_auto")
+	case data.Event == "PostToolUse" && data.Failed:
+		statusLine = i18n.T(data.Lang, "notify.tool_failed", data.Subject)
+	case data.Event == "PostToolUse":
+		statusLine = i18n.T(data.Lang, "notify.tool_done", data.Subject)
 	default:
-		emoji = "✅"
-		status = "Task Completed"
+		statusLine = i18n.T(data.Lang, "notify.task_completed")
+	}
+	if text, ok := renderTemplate(newNotificationView(data, statusLine), data.Event, NotificationTemplate); ok {
+		return text
 	}
-	statusLine := emoji + " " + status
 	if data.Page > 0 {
 		statusLine += fmt.Sprintf(" (%d/%d)", data.Page, data.TotalPages)
 	}
 	lines := []string{
 		statusLine,
-		"Project: " + projectDisplay(data.Project, data.CWD),
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
 	}
 	if data.TmuxTarget != "" {
 		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
 	}
 	if data.ContextUsedPct >= 0 {
 		used := float64(data.ContextUsedTokens)
-		usedStr := formatTokens(used)
-		totalStr := formatTokens(float64(data.ContextWindowSize))
-		lines = append(lines, fmt.Sprintf("📊 Context: %d%% (%s/%s)", data.ContextUsedPct, usedStr, totalStr))
+		usedStr := FormatTokens(used)
+		totalStr := FormatTokens(float64(data.ContextWindowSize))
+		lines = append(lines, i18n.T(data.Lang, "notify.context", data.ContextUsedPct, usedStr, totalStr))
 	}
 	if data.Body != "" {
-		lines = append(lines, "", "💬 Claude:", data.Body)
+		lines = append(lines, "")
+		if bodyFromClaude(data.Event) {
+			lines = append(lines, i18n.T(data.Lang, "notify.claude"))
+		}
+		lines = append(lines, data.Body)
 	}
 	return strings.Join(lines, "\n")
 }
 
+// bodyFromClaude reports whether an event's body is Claude's own text, which
+// gets the "Claude:" label; tool output and alerts are shown bare.
+func bodyFromClaude(event string) bool {
+	switch event {
+	case "Notification", "PreCompact", "PostToolUse":
+		return false
+	}
+	return true
+}
+
 func HeaderLen(data NotificationData) int {
 	d := data
 	d.Body = ""
@@ -137,32 +173,37 @@ func HeaderLen(data NotificationData) int {
 }
 
 func BuildPermissionText(data PermissionData) string {
+	if text, ok := renderTemplate(newPermissionView(data), PermissionTemplate); ok {
+		return text
+	}
 	lines := []string{
-		"🔐 Permission Request",
-		"Project: " + projectDisplay(data.Project, data.CWD),
+		i18n.T(data.Lang, "perm.title"),
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
 	}
 	if data.TmuxTarget != "" {
 		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
 	}
-	lines = append(lines, "", "🔧 Tool: "+data.ToolName)
-	// Show key fields from tool_input
-	for _, key := range []string{"command", "file_path", "old_string", "new_string", "replace_all", "url", "query", "pattern", "prompt"} {
-		if v, ok := data.ToolInput[key]; ok {
-			s := fmt.Sprintf("%v", v)
-			if key == "old_string" || key == "new_string" {
-				lines = append(lines, key+":\n```\n"+s+"\n```")
-			} else {
-				lines = append(lines, key+": "+s)
-			}
-		}
+	server, title := ParseToolName(data.ToolName)
+	if server != "" {
+		lines = append(lines, "", i18n.T(data.Lang, "perm.mcp", server), i18n.T(data.Lang, "perm.tool", title))
+	} else {
+		lines = append(lines, "", i18n.T(data.Lang, "perm.tool", data.ToolName))
+	}
+	if data.Edit != nil {
+		lines = append(lines, buildEditLines(data.Edit, data.Lang)...)
+		return strings.Join(lines, "\n")
 	}
+	lines = append(lines, FormatToolInput(data.ToolName, data.ToolInput)...)
 	return strings.Join(lines, "\n")
 }
 
 func BuildQuestionText(data QuestionData) string {
+	if text, ok := renderTemplate(newQuestionView(data), QuestionTemplate); ok {
+		return text
+	}
 	lines := []string{
-		"❓ Question",
-		"Project: " + projectDisplay(data.Project, data.CWD),
+		i18n.T(data.Lang, "question.title"),
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
 	}
 	if data.TmuxTarget != "" {
 		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
@@ -171,7 +212,7 @@ func BuildQuestionText(data QuestionData) string {
 		for qIdx, q := range data.Questions {
 			multiTag := ""
 			if q.MultiSelect {
-				multiTag = " (多选)"
+				multiTag = i18n.T(data.Lang, "question.multi")
 			}
 			lines = append(lines, "", fmt.Sprintf("**Q%d: %s**%s", qIdx+1, q.Header, multiTag))
 			lines = append(lines, q.Question)
@@ -208,3 +249,66 @@ func BuildQuestionText(data QuestionData) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+type ContextAlertData struct {
+	Project           string
+	CWD               string
+	TmuxTarget        string
+	Threshold         int // the configured percentage that was crossed
+	ContextUsedPct    int
+	ContextUsedTokens int
+	ContextWindowSize int
+	Lang              string
+}
+
+// ContextAlertView is the data passed to the context alert template.
+type ContextAlertView struct {
+	Title      string // localized title line
+	Project    string
+	CWD        string
+	Path       string
+	Pane       string
+	TmuxTarget string
+	Threshold  int
+	Context    ContextView
+	Lang       string
+}
+
+func BuildContextAlertText(data ContextAlertData) string {
+	title := i18n.T(data.Lang, "context.alert", data.ContextUsedPct)
+	usedStr := FormatTokens(float64(data.ContextUsedTokens))
+	totalStr := FormatTokens(float64(data.ContextWindowSize))
+	view := ContextAlertView{
+		Title:      title,
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		Threshold:  data.Threshold,
+		Context: ContextView{
+			Pct:          data.ContextUsedPct,
+			Used:         usedStr,
+			Window:       totalStr,
+			UsedTokens:   data.ContextUsedTokens,
+			WindowTokens: data.ContextWindowSize,
+		},
+		Lang: data.Lang,
+	}
+	if text, ok := renderTemplate(view, ContextAlertTemplate); ok {
+		return text
+	}
+	lines := []string{
+		title,
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
+	}
+	if data.TmuxTarget != "" {
+		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
+	}
+	lines = append(lines,
+		i18n.T(data.Lang, "notify.context", data.ContextUsedPct, usedStr, totalStr),
+		"",
+		i18n.T(data.Lang, "context.hint"),
+	)
+	return strings.Join(lines, "\n")
+}
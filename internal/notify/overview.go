@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// OverviewTemplate is the template name for the pinned per-chat overview.
+const OverviewTemplate = "overview"
+
+// OverviewSession is one session line of the chat overview.
+type OverviewSession struct {
+	TmuxTarget     string
+	Pane           string
+	Project        string
+	CWD            string
+	Path           string
+	Running        bool
+	PermMode       string // default, plan, auto, bypass; empty when unknown
+	ContextUsedPct int    // -1 means no data
+	Pending        int    // unanswered permission requests and questions
+	Active         bool   // picked with /bot_use as the chat's active session
+	LastEvent      time.Time
+	Since          string // localized time since LastEvent
+}
+
+type OverviewData struct {
+	Sessions []OverviewSession
+	Updated  time.Time
+	Lang     string
+}
+
+// OverviewView is the data passed to the overview template.
+type OverviewView struct {
+	Title    string
+	Sessions []OverviewSession
+	Updated  string // local time of the last refresh, "15:04"
+	Lang     string
+}
+
+// FormatSince renders the time elapsed since t as e.g. "now", "5m", "2h" or "3d".
+func FormatSince(t time.Time, lang string) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return i18n.T(lang, "overview.now")
+	case d < time.Hour:
+		return i18n.T(lang, "overview.minutes", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return i18n.T(lang, "overview.hours", int(d.Hours()))
+	}
+	return i18n.T(lang, "overview.days", int(d.Hours()/24))
+}
+
+// BuildOverviewText renders the chat overview. The default text has no clock
+// or relative times, so it only changes, and is only edited, when a
+// session's state does.
+func BuildOverviewText(data OverviewData) string {
+	sessions := make([]OverviewSession, len(data.Sessions))
+	for i, s := range data.Sessions {
+		s.Pane = FormatPaneID(s.TmuxTarget)
+		s.Path = projectDisplay(s.Project, s.CWD)
+		s.Since = FormatSince(s.LastEvent, data.Lang)
+		sessions[i] = s
+	}
+	title := i18n.N(data.Lang, "overview.title", len(sessions))
+	updated := data.Updated.Local().Format("15:04")
+	view := OverviewView{Title: title, Sessions: sessions, Updated: updated, Lang: data.Lang}
+	if text, ok := renderTemplate(view, OverviewTemplate); ok {
+		return text
+	}
+	lines := []string{title, ""}
+	if len(sessions) == 0 {
+		lines = append(lines, i18n.T(data.Lang, "overview.none"))
+	}
+	for _, s := range sessions {
+		state := i18n.T(data.Lang, "overview.idle")
+		icon := "⚪"
+		if s.Running {
+			state = i18n.T(data.Lang, "overview.running")
+			icon = "🟢"
+		}
+		if s.Pending > 0 {
+			icon = "🟡"
+		}
+		head := fmt.Sprintf("%s %s %s", icon, s.Pane, s.Path)
+		if s.Active {
+			head += " 📌"
+		}
+		parts := []string{head, state}
+		if s.PermMode != "" {
+			parts = append(parts, i18n.T(data.Lang, "overview.mode."+s.PermMode))
+		}
+		if s.ContextUsedPct >= 0 {
+			parts = append(parts, fmt.Sprintf("📊 %d%%", s.ContextUsedPct))
+		}
+		if s.Pending > 0 {
+			parts = append(parts, fmt.Sprintf("🔐 %d", s.Pending))
+		}
+		lines = append(lines, strings.Join(parts, " · "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// PendingItem is one unanswered permission request or question.
+type PendingItem struct {
+	Summary  string // e.g. "Bash: npm test"
+	Question bool   // AskUserQuestion, answered on its own message only
+	Plan     bool   // ExitPlanMode, answered on its own message only
+	Created  time.Time
+}
+
+// PendingSession groups the unanswered requests of one session.
+type PendingSession struct {
+	TmuxTarget string
+	Project    string
+	CWD        string
+	Items      []PendingItem
+}
+
+type PendingData struct {
+	Sessions []PendingSession
+	Lang     string
+}
+
+// BuildPendingText renders the /bot_pending list, numbering sessions so the
+// batch buttons can refer to them.
+func BuildPendingText(data PendingData) string {
+	total := 0
+	for _, s := range data.Sessions {
+		total += len(s.Items)
+	}
+	if total == 0 {
+		return i18n.T(data.Lang, "pending.none")
+	}
+	lines := []string{i18n.N(data.Lang, "pending.title", total, total)}
+	for i, s := range data.Sessions {
+		lines = append(lines, "", fmt.Sprintf("%d. 📁 %s · 📟 %s", i+1, projectDisplay(s.Project, s.CWD), FormatPaneID(s.TmuxTarget)))
+		for _, it := range s.Items {
+			icon := "🔐"
+			if it.Question {
+				icon = "❓"
+			} else if it.Plan {
+				icon = "📋"
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s · %s", icon, it.Summary, FormatSince(it.Created, data.Lang)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
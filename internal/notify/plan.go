@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// PlanData describes the session of an ExitPlanMode request.
+type PlanData struct {
+	Project    string
+	CWD        string
+	TmuxTarget string
+	Lang       string
+}
+
+// BuildPlanHeader returns the lines shown above every page of a plan.
+func BuildPlanHeader(data PlanData) string {
+	lines := []string{
+		i18n.T(data.Lang, "plan.title"),
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
+	}
+	if data.TmuxTarget != "" {
+		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
+	}
+	return strings.Join(lines, "\n")
+}
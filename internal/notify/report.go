@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/report"
+)
+
+// SessionReportTemplate is the template name for the end-of-session report.
+const SessionReportTemplate = "session_report"
+
+const (
+	maxReportFiles    = 15
+	maxReportStatRows = 20
+)
+
+type SessionReportData struct {
+	Project    string
+	CWD        string
+	TmuxTarget string
+	Summary    report.Summary
+	Git        bool   // whether CWD is inside a git work tree
+	BaseCommit string // commit the session started from; empty when unknown
+	DiffStat   string // output of git diff --stat against BaseCommit
+	Lang       string
+}
+
+// SessionReportView is the data passed to the session_report template.
+type SessionReportView struct {
+	Title      string
+	Project    string
+	CWD        string
+	Path       string
+	Pane       string
+	TmuxTarget string
+	Duration   string // e.g. "1h 12m"
+	Turns      int
+	Tools      []report.ToolCount
+	Files      []string // touched files, relative to CWD when inside it
+	BaseCommit string   // short commit hash
+	DiffStat   string
+	Lang       string
+}
+
+// FormatDuration renders a duration as e.g. "45s", "12m" or "1h 12m".
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// relPath shows path relative to cwd when it lies inside it.
+func relPath(path, cwd string) string {
+	if cwd != "" {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return CompressPath(path)
+}
+
+func BuildSessionReportText(data SessionReportData) string {
+	files := make([]string, len(data.Summary.Files))
+	for i, f := range data.Summary.Files {
+		files[i] = relPath(f, data.CWD)
+	}
+	base := data.BaseCommit
+	if len(base) > 7 {
+		base = base[:7]
+	}
+	view := SessionReportView{
+		Title:      i18n.T(data.Lang, "notify.session_ended"),
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		Duration:   FormatDuration(data.Summary.Duration()),
+		Turns:      data.Summary.Turns,
+		Tools:      data.Summary.ToolCounts(),
+		Files:      files,
+		BaseCommit: base,
+		DiffStat:   strings.TrimRight(data.DiffStat, "\n"),
+		Lang:       data.Lang,
+	}
+	if text, ok := renderTemplate(view, SessionReportTemplate); ok {
+		return text
+	}
+	lines := []string{view.Title, i18n.T(data.Lang, "notify.project", view.Path)}
+	if view.Pane != "" {
+		lines = append(lines, "📟 "+view.Pane)
+	}
+	lines = append(lines, "", i18n.T(data.Lang, "report.duration", view.Duration)+" · "+i18n.N(data.Lang, "report.turns", view.Turns))
+	if len(view.Tools) > 0 {
+		tools := make([]string, len(view.Tools))
+		for i, t := range view.Tools {
+			tools[i] = fmt.Sprintf("%s ×%d", t.Name, t.Count)
+		}
+		lines = append(lines, "🔧 "+strings.Join(tools, ", "))
+	}
+	if len(files) > 0 {
+		lines = append(lines, "", i18n.T(data.Lang, "report.files", len(files)))
+		for i, f := range files {
+			if i == maxReportFiles {
+				lines = append(lines, "  "+i18n.N(data.Lang, "report.more_files", len(files)-maxReportFiles))
+				break
+			}
+			lines = append(lines, "  "+f)
+		}
+	}
+	if view.DiffStat != "" {
+		title := i18n.T(data.Lang, "report.git_uncommitted")
+		if base != "" {
+			title = i18n.T(data.Lang, "report.git_since", base)
+		}
+		lines = append(lines, "", title)
+		rows := strings.Split(view.DiffStat, "\n")
+		if len(rows) > maxReportStatRows+1 {
+			// Keep the "N files changed" summary line
+			rows = append(rows[:maxReportStatRows], " …", rows[len(rows)-1])
+		}
+		lines = append(lines, rows...)
+	} else if data.Git {
+		lines = append(lines, "", i18n.T(data.Lang, "report.git_clean"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,240 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+	"github.com/Seraphli/tg-cli/internal/logger"
+)
+
+// Template names looked up in the template directory as <name>.tmpl.
+// Notifications first try the event name (e.g. Stop.tmpl, SessionStart.tmpl)
+// and then the generic notification.tmpl.
+const (
+	NotificationTemplate = "notification"
+	PermissionTemplate   = "permission"
+	QuestionTemplate     = "question"
+	ContextAlertTemplate = "context_alert"
+)
+
+// ContextView is the context window usage shown in notifications.
+type ContextView struct {
+	Pct          int    // percentage of the auto-compact limit in use
+	Used         string // formatted, e.g. "45.2k"
+	Window       string // formatted, e.g. "160.0k"
+	UsedTokens   int
+	WindowTokens int
+}
+
+// NotificationView is the data passed to notification templates.
+type NotificationView struct {
+	Event      string // SessionStart, SessionEnd, PreToolUse, Stop, Notification, SubagentStop, PreCompact, PostToolUse
+	Subject    string // tool name, subagent type or compaction trigger
+	Failed     bool   // PostToolUse: the tool reported an error
+	Status     string // localized status line, without the page counter
+	Project    string
+	CWD        string
+	Path       string // compressed CWD, or Project when CWD is empty
+	Pane       string // tmux pane ID, e.g. "%3"
+	TmuxTarget string // full tmux target including the socket
+	Body       string // message body (the current page when paginated)
+	Page       int    // 1-based page, 0 when not paginated
+	TotalPages int
+	Context    *ContextView // nil when usage is unknown
+	Lang       string
+}
+
+// PermissionView is the data passed to the permission template.
+type PermissionView struct {
+	Title      string // localized title line
+	Project    string
+	CWD        string
+	Path       string
+	Pane       string
+	TmuxTarget string
+	ToolName   string                 // raw tool name, e.g. "mcp__github__create_issue"
+	Tool       string                 // display name, e.g. "Create Issue"
+	MCPServer  string                 // empty for built-in tools
+	Input      map[string]interface{} // raw tool input
+	InputLines []string               // rendered tool input, as in the built-in layout
+	Edit       *EditPreview           // set for Edit, MultiEdit and Write
+	Lang       string
+}
+
+// QuestionView is the data passed to the question template.
+type QuestionView struct {
+	Title      string // localized title line
+	Project    string
+	CWD        string
+	Path       string
+	Pane       string
+	TmuxTarget string
+	Questions  []QuestionEntry
+	Lang       string
+}
+
+type cachedTemplate struct {
+	modTime time.Time
+	tmpl    *template.Template
+}
+
+var (
+	templateMu    sync.Mutex
+	templateDir   string
+	templateCache = make(map[string]*cachedTemplate)
+)
+
+// SetTemplateDir sets the directory user templates are loaded from.
+// An empty dir disables templates.
+func SetTemplateDir(dir string) {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	templateDir = dir
+	templateCache = make(map[string]*cachedTemplate)
+}
+
+var templateFuncs = template.FuncMap{
+	"t":        i18n.T,
+	"join":     strings.Join,
+	"compress": CompressPath,
+	"pane":     FormatPaneID,
+	"add":      func(a, b int) int { return a + b },
+	"truncate": func(n int, s string) string {
+		r := []rune(s)
+		if len(r) <= n {
+			return s
+		}
+		return string(r[:n]) + "…"
+	},
+}
+
+// loadTemplate returns the parsed template for name, reparsing when the file
+// changes. It returns nil when the file doesn't exist or fails to parse.
+func loadTemplate(name string) *template.Template {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	if templateDir == "" {
+		return nil
+	}
+	path := filepath.Join(templateDir, name+".tmpl")
+	info, err := os.Stat(path)
+	if err != nil {
+		delete(templateCache, name)
+		return nil
+	}
+	if c, ok := templateCache[name]; ok && c.modTime.Equal(info.ModTime()) {
+		return c.tmpl
+	}
+	c := &cachedTemplate{modTime: info.ModTime()}
+	src, err := os.ReadFile(path)
+	if err == nil {
+		c.tmpl, err = template.New(name).Funcs(templateFuncs).Parse(string(src))
+	}
+	if err != nil {
+		c.tmpl = nil
+		logger.Error(fmt.Sprintf("Template %s invalid, using built-in layout: %v", path, err))
+	}
+	templateCache[name] = c
+	return c.tmpl
+}
+
+// renderTemplate executes the first existing template among names. ok is false
+// when none exists, execution fails or the output is empty.
+func renderTemplate(data interface{}, names ...string) (string, bool) {
+	for _, name := range names {
+		tmpl := loadTemplate(name)
+		if tmpl == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			logger.Error(fmt.Sprintf("Template %s failed, using built-in layout: %v", name, err))
+			return "", false
+		}
+		out := strings.TrimSpace(buf.String())
+		if out == "" {
+			return "", false
+		}
+		return out, true
+	}
+	return "", false
+}
+
+func newNotificationView(data NotificationData, status string) NotificationView {
+	v := NotificationView{
+		Event:      data.Event,
+		Subject:    data.Subject,
+		Failed:     data.Failed,
+		Status:     status,
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		Body:       data.Body,
+		Page:       data.Page,
+		TotalPages: data.TotalPages,
+		Lang:       data.Lang,
+	}
+	if data.ContextUsedPct >= 0 {
+		v.Context = &ContextView{
+			Pct:          data.ContextUsedPct,
+			Used:         FormatTokens(float64(data.ContextUsedTokens)),
+			Window:       FormatTokens(float64(data.ContextWindowSize)),
+			UsedTokens:   data.ContextUsedTokens,
+			WindowTokens: data.ContextWindowSize,
+		}
+	}
+	return v
+}
+
+func newPermissionView(data PermissionData) PermissionView {
+	server, title := ParseToolName(data.ToolName)
+	if server == "" {
+		title = data.ToolName
+	}
+	v := PermissionView{
+		Title:      i18n.T(data.Lang, "perm.title"),
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		ToolName:   data.ToolName,
+		Tool:       title,
+		MCPServer:  server,
+		Input:      data.ToolInput,
+		Edit:       data.Edit,
+		Lang:       data.Lang,
+	}
+	if data.Edit != nil {
+		v.InputLines = buildEditLines(data.Edit, data.Lang)
+	} else {
+		v.InputLines = FormatToolInput(data.ToolName, data.ToolInput)
+	}
+	return v
+}
+
+func newQuestionView(data QuestionData) QuestionView {
+	questions := data.Questions
+	if len(questions) == 0 && data.Question != "" {
+		questions = []QuestionEntry{{Header: data.Header, Question: data.Question, Options: data.Options}}
+	}
+	return QuestionView{
+		Title:      i18n.T(data.Lang, "question.title"),
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		Questions:  questions,
+		Lang:       data.Lang,
+	}
+}
@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/i18n"
+)
+
+// TodoTemplate is the template name for the TodoWrite checklist message.
+const TodoTemplate = "todo"
+
+// TodoItem is one entry of a TodoWrite task list.
+type TodoItem struct {
+	Content    string
+	ActiveForm string // present-tense label shown while in progress
+	Status     string // pending, in_progress or completed
+}
+
+type TodoData struct {
+	Project    string
+	CWD        string
+	TmuxTarget string
+	Todos      []TodoItem
+	Lang       string
+}
+
+// TodoView is the data passed to the todo template.
+type TodoView struct {
+	Title      string // localized title line with progress counts
+	Project    string
+	CWD        string
+	Path       string
+	Pane       string
+	TmuxTarget string
+	Todos      []TodoItem
+	Done       int
+	Total      int
+	Lang       string
+}
+
+// ParseTodos reads the task list from a TodoWrite tool input.
+func ParseTodos(toolInput map[string]interface{}) []TodoItem {
+	list, _ := toolInput["todos"].([]interface{})
+	var todos []TodoItem
+	for _, t := range list {
+		m, _ := t.(map[string]interface{})
+		if m == nil {
+			continue
+		}
+		todos = append(todos, TodoItem{
+			Content:    stringField(m, "content"),
+			ActiveForm: stringField(m, "activeForm"),
+			Status:     stringField(m, "status"),
+		})
+	}
+	return todos
+}
+
+// todoMark returns the checkbox for a status and the label to show.
+func todoMark(t TodoItem) (string, string) {
+	switch t.Status {
+	case "completed":
+		return "☑", t.Content
+	case "in_progress":
+		if t.ActiveForm != "" {
+			return "◐", t.ActiveForm
+		}
+		return "◐", t.Content
+	}
+	return "☐", t.Content
+}
+
+func BuildTodoText(data TodoData) string {
+	done := 0
+	for _, t := range data.Todos {
+		if t.Status == "completed" {
+			done++
+		}
+	}
+	title := i18n.T(data.Lang, "todo.title", done, len(data.Todos))
+	view := TodoView{
+		Title:      title,
+		Project:    data.Project,
+		CWD:        data.CWD,
+		Path:       projectDisplay(data.Project, data.CWD),
+		Pane:       FormatPaneID(data.TmuxTarget),
+		TmuxTarget: data.TmuxTarget,
+		Todos:      data.Todos,
+		Done:       done,
+		Total:      len(data.Todos),
+		Lang:       data.Lang,
+	}
+	if text, ok := renderTemplate(view, TodoTemplate); ok {
+		return text
+	}
+	lines := []string{
+		title,
+		i18n.T(data.Lang, "notify.project", projectDisplay(data.Project, data.CWD)),
+	}
+	if data.TmuxTarget != "" {
+		lines = append(lines, "📟 "+FormatPaneID(data.TmuxTarget))
+	}
+	lines = append(lines, "")
+	if len(data.Todos) == 0 {
+		lines = append(lines, i18n.T(data.Lang, "todo.empty"))
+	}
+	for _, t := range data.Todos {
+		mark, label := todoMark(t)
+		lines = append(lines, fmt.Sprintf("%s %s", mark, label))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits for rendering tool input values. Each field is capped so one huge
+// value can't take over the message; what remains is paginated.
+const (
+	maxInlineValueLen = 120  // longer strings are fenced
+	maxBlockValueLen  = 1500 // fenced strings and compact JSON are cut here
+	maxListItems      = 10
+	maxInputDepth     = 3 // deeper values are shown as compact JSON
+)
+
+// toolFormatter lists the keys shown first for a tool and those rendered as
+// fenced blocks. Remaining keys follow in sorted order.
+type toolFormatter struct {
+	order  []string
+	blocks []string
+}
+
+var toolFormatters = map[string]toolFormatter{
+	"Bash":         {order: []string{"command", "description", "timeout", "run_in_background"}, blocks: []string{"command"}},
+	"ExitPlanMode": {order: []string{"plan"}, blocks: []string{"plan"}},
+	"Task":         {order: []string{"description", "subagent_type", "prompt"}, blocks: []string{"prompt"}},
+	"WebSearch":    {order: []string{"query", "allowed_domains", "blocked_domains"}},
+	"WebFetch":     {order: []string{"url", "prompt"}},
+	"NotebookEdit": {order: []string{"notebook_path", "cell_id", "cell_type", "edit_mode", "new_source"}, blocks: []string{"new_source"}},
+	"Read":         {order: []string{"file_path", "offset", "limit"}},
+	"Glob":         {order: []string{"pattern", "path"}},
+	"Grep":         {order: []string{"pattern", "path", "glob", "type", "output_mode"}},
+}
+
+// defaultFormatter applies to tools without a dedicated formatter.
+var defaultFormatter = toolFormatter{
+	order: []string{"command", "file_path", "path", "url", "query", "pattern", "description", "prompt"},
+}
+
+// ParseToolName splits an MCP tool name (mcp__<server>__<tool>) into the
+// server name and a human-readable title. Built-in tools return an empty
+// server and the name unchanged.
+func ParseToolName(name string) (server, title string) {
+	rest, ok := strings.CutPrefix(name, "mcp__")
+	if !ok {
+		return "", name
+	}
+	server, tool, ok := strings.Cut(rest, "__")
+	if !ok {
+		return rest, rest
+	}
+	return server, humanize(tool)
+}
+
+// humanize turns snake_case or kebab-case identifiers into "Sentence case".
+func humanize(s string) string {
+	s = strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(s))
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// FormatToolInput renders every field of a tool input, nested values
+// indented, long or multi-line strings fenced and over-long values cut.
+func FormatToolInput(toolName string, input map[string]interface{}) []string {
+	f, ok := toolFormatters[toolName]
+	if !ok {
+		f = defaultFormatter
+	}
+	var lines []string
+	for _, key := range orderedKeys(input, f) {
+		lines = append(lines, formatField(key, input[key], 0, contains(f.blocks, key))...)
+	}
+	return lines
+}
+
+func orderedKeys(input map[string]interface{}, f toolFormatter) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, k := range f.order {
+		if _, ok := input[k]; ok {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	var rest []string
+	for k := range input {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func formatField(key string, v interface{}, depth int, block bool) []string {
+	indent := strings.Repeat("  ", depth)
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			return []string{indent + key + ": {}"}
+		}
+		if depth >= maxInputDepth {
+			return []string{indent + key + ": " + truncateRunes(compactJSON(val), maxBlockValueLen)}
+		}
+		lines := []string{indent + key + ":"}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			lines = append(lines, formatField(k, val[k], depth+1, false)...)
+		}
+		return lines
+	case []interface{}:
+		if len(val) == 0 {
+			return []string{indent + key + ": []"}
+		}
+		if allScalars(val) {
+			var items []string
+			for _, item := range val {
+				items = append(items, scalarString(item))
+			}
+			inline := strings.Join(items, ", ")
+			if len([]rune(inline)) <= maxInlineValueLen {
+				return []string{indent + key + ": [" + inline + "]"}
+			}
+		}
+		if depth >= maxInputDepth {
+			return []string{indent + key + ": " + truncateRunes(compactJSON(val), maxBlockValueLen)}
+		}
+		lines := []string{indent + key + ":"}
+		for i, item := range val {
+			if i == maxListItems {
+				lines = append(lines, fmt.Sprintf("%s  … %d more", indent, len(val)-maxListItems))
+				break
+			}
+			lines = append(lines, formatField(fmt.Sprintf("[%d]", i+1), item, depth+1, false)...)
+		}
+		return lines
+	case string:
+		if block || strings.Contains(val, "\n") || len([]rune(val)) > maxInlineValueLen {
+			return []string{indent + key + ":\n```\n" + truncateRunes(val, maxBlockValueLen) + "\n```"}
+		}
+		return []string{indent + key + ": " + val}
+	default:
+		return []string{indent + key + ": " + scalarString(val)}
+	}
+}
+
+func allScalars(list []interface{}) bool {
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		case string:
+			if strings.Contains(item.(string), "\n") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func scalarString(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		// JSON numbers decode as float64; avoid exponent notation for integers
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// truncateRunes cuts s to max runes, noting how much was dropped.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + fmt.Sprintf("… (%d more chars)", len(runes)-max)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolSummary returns a one-line description of a tool call, e.g.
+// "Bash: npm test", from the first string field its formatter lists.
+func ToolSummary(toolName string, input map[string]interface{}) string {
+	f, ok := toolFormatters[toolName]
+	if !ok {
+		f = defaultFormatter
+	}
+	_, title := ParseToolName(toolName)
+	for _, key := range f.order {
+		if s, ok := input[key].(string); ok && strings.TrimSpace(s) != "" {
+			return title + ": " + truncateRunes(firstLine(s), maxInlineValueLen)
+		}
+	}
+	return title
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToolResult is the outcome of a tool call reported by PostToolUse.
+type ToolResult struct {
+	Tool    string // display name; MCP tools are humanized
+	Summary string // the tool's primary input, e.g. the command or file path
+	Output  string // text output, empty when the response has none
+	Failed  bool
+}
+
+// ParseToolResult extracts the text output and error state from a
+// PostToolUse tool_response, whose shape depends on the tool.
+func ParseToolResult(toolName string, input map[string]interface{}, response interface{}) ToolResult {
+	r := ToolResult{Tool: toolName}
+	if server, title := ParseToolName(toolName); server != "" {
+		r.Tool = server + ": " + title
+	}
+	f, ok := toolFormatters[toolName]
+	if !ok {
+		f = defaultFormatter
+	}
+	for _, key := range f.order {
+		if s, ok := input[key].(string); ok && s != "" {
+			r.Summary = truncateRunes(firstLine(s), maxInlineValueLen)
+			break
+		}
+	}
+	r.Output, r.Failed = responseText(response)
+	return r
+}
+
+// Body renders the summary and output, the output cut to maxOutput runes
+// (0 means no limit).
+func (r ToolResult) Body(maxOutput int) string {
+	var lines []string
+	if r.Summary != "" {
+		lines = append(lines, r.Summary)
+	}
+	if out := strings.TrimSpace(r.Output); out != "" {
+		if maxOutput > 0 {
+			out = truncateRunes(out, maxOutput)
+		}
+		lines = append(lines, "```\n"+out+"\n```")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func responseText(resp interface{}) (string, bool) {
+	switch v := resp.(type) {
+	case string:
+		return v, false
+	case []interface{}:
+		return contentText(v), false
+	case map[string]interface{}:
+		failed := isTrue(v["is_error"]) || isTrue(v["isError"]) || isTrue(v["interrupted"]) || v["success"] == false
+		if code, ok := v["exit_code"].(float64); ok && code != 0 {
+			failed = true
+		}
+		if e, ok := v["error"].(string); ok && e != "" {
+			return e, true
+		}
+		var parts []string
+		for _, key := range []string{"stdout", "stderr", "output", "result"} {
+			if s, ok := v[key].(string); ok && strings.TrimSpace(s) != "" {
+				parts = append(parts, s)
+			}
+		}
+		switch c := v["content"].(type) {
+		case string:
+			parts = append(parts, c)
+		case []interface{}:
+			parts = append(parts, contentText(c))
+		}
+		return strings.Join(parts, "\n"), failed
+	case nil:
+		return "", false
+	}
+	return fmt.Sprint(resp), false
+}
+
+// contentText joins the text blocks of an MCP-style content list.
+func contentText(blocks []interface{}) string {
+	var texts []string
+	for _, b := range blocks {
+		m, _ := b.(map[string]interface{})
+		if s, ok := m["text"].(string); ok {
+			texts = append(texts, s)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func isTrue(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,140 @@
+// Package report summarizes a Claude Code session from its transcript.
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileTools are the tools whose file path argument counts as a touched file.
+var fileTools = map[string]string{
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"Write":        "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+// ToolCount is the number of calls of one tool.
+type ToolCount struct {
+	Name  string
+	Count int
+}
+
+// Summary is what happened in a session.
+type Summary struct {
+	Start time.Time
+	End   time.Time
+	Turns int            // user prompts, excluding tool results and commands
+	Tools map[string]int // tool name → calls
+	Files []string       // files edited or written, in first-touched order
+}
+
+// Duration returns the time between the first and last transcript entries.
+func (s Summary) Duration() time.Duration {
+	if s.Start.IsZero() || s.End.Before(s.Start) {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// ToolCounts returns the tool calls ordered by count, then name.
+func (s Summary) ToolCounts() []ToolCount {
+	counts := make([]ToolCount, 0, len(s.Tools))
+	for name, n := range s.Tools {
+		counts = append(counts, ToolCount{Name: name, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// Summarize reads a transcript and collects its duration, turns, tool calls
+// and touched files. Subagent (sidechain) entries are skipped.
+func Summarize(r io.Reader) Summary {
+	s := Summary{Tools: make(map[string]int)}
+	seenFiles := make(map[string]bool)
+	seenTools := make(map[string]bool)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for sc.Scan() {
+		var line struct {
+			Type        string    `json:"type"`
+			Timestamp   time.Time `json:"timestamp"`
+			IsSidechain bool      `json:"isSidechain"`
+			IsMeta      bool      `json:"isMeta"`
+			Message     struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(sc.Bytes(), &line) != nil || line.IsSidechain {
+			continue
+		}
+		if line.Type != "user" && line.Type != "assistant" {
+			continue
+		}
+		if !line.Timestamp.IsZero() {
+			if s.Start.IsZero() || line.Timestamp.Before(s.Start) {
+				s.Start = line.Timestamp
+			}
+			if line.Timestamp.After(s.End) {
+				s.End = line.Timestamp
+			}
+		}
+		var text string
+		var blocks []struct {
+			Type  string                 `json:"type"`
+			ID    string                 `json:"id"`
+			Name  string                 `json:"name"`
+			Text  string                 `json:"text"`
+			Input map[string]interface{} `json:"input"`
+		}
+		if json.Unmarshal(line.Message.Content, &text) != nil {
+			json.Unmarshal(line.Message.Content, &blocks)
+		}
+		if line.Type == "user" {
+			if !line.IsMeta && isPrompt(text, len(blocks) > 0) {
+				s.Turns++
+			} else if text == "" && !line.IsMeta {
+				for _, b := range blocks {
+					if b.Type == "text" && isPrompt(b.Text, false) {
+						s.Turns++
+						break
+					}
+				}
+			}
+			continue
+		}
+		for _, b := range blocks {
+			if b.Type != "tool_use" || (b.ID != "" && seenTools[b.ID]) {
+				continue
+			}
+			seenTools[b.ID] = true
+			s.Tools[b.Name]++
+			if key, ok := fileTools[b.Name]; ok {
+				if path, _ := b.Input[key].(string); path != "" && !seenFiles[path] {
+					seenFiles[path] = true
+					s.Files = append(s.Files, path)
+				}
+			}
+		}
+	}
+	return s
+}
+
+// isPrompt reports whether user text is a typed prompt rather than a slash
+// command or system-injected tag block.
+func isPrompt(text string, hasBlocks bool) bool {
+	text = strings.TrimSpace(text)
+	if text == "" || hasBlocks {
+		return false
+	}
+	return !strings.HasPrefix(text, "<")
+}
@@ -0,0 +1,43 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"user","timestamp":"2026-03-04T10:00:00Z","message":{"content":"fix the bug"}}`,
+		`{"type":"assistant","timestamp":"2026-03-04T10:00:05Z","message":{"content":[{"type":"text","text":"ok"},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/a.go"}}]}}`,
+		`{"type":"user","timestamp":"2026-03-04T10:00:06Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"..."}]}}`,
+		`{"type":"assistant","timestamp":"2026-03-04T10:01:00Z","message":{"content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/p/a.go"}}]}}`,
+		`{"type":"assistant","timestamp":"2026-03-04T10:01:00Z","message":{"content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/p/a.go"}}]}}`,
+		`{"type":"assistant","timestamp":"2026-03-04T10:02:00Z","isSidechain":true,"message":{"content":[{"type":"tool_use","id":"s1","name":"Write","input":{"file_path":"/p/side.go"}}]}}`,
+		`{"type":"user","timestamp":"2026-03-04T10:05:00Z","message":{"content":"<command-name>/clear</command-name>"}}`,
+		`{"type":"user","timestamp":"2026-03-04T10:06:00Z","isMeta":true,"message":{"content":"caveat"}}`,
+		`{"type":"user","timestamp":"2026-03-04T10:10:00Z","message":{"content":[{"type":"text","text":"now add a test"}]}}`,
+		`{"type":"assistant","timestamp":"2026-03-04T10:12:30Z","message":{"content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/p/a_test.go"}},{"type":"tool_use","id":"t4","name":"Edit","input":{"file_path":"/p/a.go"}}]}}`,
+		`not json`,
+	}, "\n")
+	s := Summarize(strings.NewReader(transcript))
+	if s.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", s.Turns)
+	}
+	if d := s.Duration(); d != 12*time.Minute+30*time.Second {
+		t.Errorf("Duration = %v", d)
+	}
+	if got := strings.Join(s.Files, ","); got != "/p/a.go,/p/a_test.go" {
+		t.Errorf("Files = %s", got)
+	}
+	want := []ToolCount{{"Edit", 2}, {"Read", 1}, {"Write", 1}}
+	got := s.ToolCounts()
+	if len(got) != len(want) {
+		t.Fatalf("ToolCounts = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ToolCounts[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
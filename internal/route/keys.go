@@ -0,0 +1,121 @@
+package route
+
+import "strings"
+
+// Prefixes of the stable RouteMap keys. Keys without one are legacy tmux
+// pane IDs such as "%12@/tmp/tmux-1000/default", which tmux reassigns after
+// a restart.
+const (
+	PrefixAlias  = "alias:"  // alias:<name>, a user-assigned session alias
+	PrefixClaude = "claude:" // claude:<id>, the first session ID of a resume chain
+	PrefixPane   = "pane:"   // pane:<session>:<window>.<pane>[@socket]
+)
+
+// Identity is what a session's routes can be keyed by. Empty fields are
+// unknown.
+type Identity struct {
+	Alias      string
+	ClaudeRoot string
+	Location   string // tmux "session:window.pane"
+	Socket     string // tmux socket, empty for the default one
+	PaneID     string // tmux pane ID such as "%12"
+}
+
+// AliasKey returns the RouteMap key of a session alias.
+func AliasKey(alias string) string { return PrefixAlias + alias }
+
+// ClaudeKey returns the RouteMap key of a Claude session resume chain.
+func ClaudeKey(rootSessionID string) string { return PrefixClaude + rootSessionID }
+
+// PaneKey returns the RouteMap key of a tmux pane location.
+func PaneKey(location, socket string) string {
+	if socket != "" {
+		return PrefixPane + location + "@" + socket
+	}
+	return PrefixPane + location
+}
+
+// ParsePaneKey returns the location and socket of a pane key.
+func ParsePaneKey(key string) (location, socket string, ok bool) {
+	rest, ok := strings.CutPrefix(key, PrefixPane)
+	if !ok {
+		return "", "", false
+	}
+	location, socket, _ = strings.Cut(rest, "@")
+	return location, socket, true
+}
+
+// IsLegacy reports whether key is a pane ID key from before stable keys.
+func IsLegacy(key string) bool {
+	return strings.HasPrefix(key, "%")
+}
+
+// LegacyKey returns the pane ID key of the identity, as FormatTarget writes it.
+func (id Identity) LegacyKey() string {
+	if id.PaneID == "" {
+		return ""
+	}
+	if id.Socket != "" {
+		return id.PaneID + "@" + id.Socket
+	}
+	return id.PaneID
+}
+
+// Keys returns every RouteMap key of the identity, most specific first:
+// alias, resume chain, pane location, legacy pane ID.
+func (id Identity) Keys() []string {
+	var keys []string
+	if id.Alias != "" {
+		keys = append(keys, AliasKey(id.Alias))
+	}
+	if id.ClaudeRoot != "" {
+		keys = append(keys, ClaudeKey(id.ClaudeRoot))
+	}
+	if id.Location != "" {
+		keys = append(keys, PaneKey(id.Location, id.Socket))
+	}
+	if k := id.LegacyKey(); k != "" {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// PaneRouteKey returns the key of the pane: its location when known, else
+// its legacy pane ID.
+func (id Identity) PaneRouteKey() string {
+	if id.Location != "" {
+		return PaneKey(id.Location, id.Socket)
+	}
+	return id.LegacyKey()
+}
+
+// SessionRouteKey returns the key of the session itself: its alias when set,
+// else its resume chain, or "" when neither is known.
+func (id Identity) SessionRouteKey() string {
+	if id.Alias != "" {
+		return AliasKey(id.Alias)
+	}
+	if id.ClaudeRoot != "" {
+		return ClaudeKey(id.ClaudeRoot)
+	}
+	return ""
+}
+
+// Label renders a RouteMap key for display, e.g. "🏷 api" or "📟 main:1.0".
+func Label(key string) string {
+	switch {
+	case strings.HasPrefix(key, PrefixAlias):
+		return "🏷 " + strings.TrimPrefix(key, PrefixAlias)
+	case strings.HasPrefix(key, PrefixClaude):
+		id := strings.TrimPrefix(key, PrefixClaude)
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		return "🔗 " + id
+	case strings.HasPrefix(key, PrefixPane):
+		location, _, _ := ParsePaneKey(key)
+		return "📟 " + location
+	}
+	paneID, _, _ := strings.Cut(key, "@")
+	return "📟 " + paneID
+}
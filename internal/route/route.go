@@ -0,0 +1,227 @@
+// Package route matches sessions against the ordered routing rules of
+// config.Credentials.
+package route
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+)
+
+// Keys passed to Session.Lookup.
+const (
+	KeyRemote      = "remote"
+	KeyBranch      = "branch"
+	KeyTmuxSession = "tmuxSession"
+	KeyTmuxWindow  = "tmuxWindow"
+)
+
+// Session describes the session being routed. Git and tmux facts cost a
+// process each, so they are fetched through Lookup, at most once per key and
+// only when a rule tests them.
+type Session struct {
+	CWD    string
+	Env    string // TG_CLI_ROUTE exported in the session's environment
+	Lookup func(key string) string
+
+	cache map[string]string
+}
+
+func (s *Session) fact(key string) string {
+	if v, ok := s.cache[key]; ok {
+		return v
+	}
+	v := ""
+	if s.Lookup != nil {
+		v = s.Lookup(key)
+	}
+	if s.cache == nil {
+		s.cache = make(map[string]string)
+	}
+	s.cache[key] = v
+	return v
+}
+
+// Match returns the index of the first rule that matches s.
+func Match(rules []config.RouteRule, s *Session) (int, bool) {
+	for i, r := range rules {
+		if Matches(r, s) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Matches reports whether every condition set on r holds for s.
+func Matches(r config.RouteRule, s *Session) bool {
+	if !hasConditions(r) {
+		return false
+	}
+	if r.CWD != "" && !matchDir(r.CWD, s.CWD) {
+		return false
+	}
+	if r.Env != "" && !glob(r.Env, s.Env) {
+		return false
+	}
+	if r.Remote != "" && !matchRemote(r.Remote, s.fact(KeyRemote)) {
+		return false
+	}
+	if r.Branch != "" && !glob(r.Branch, s.fact(KeyBranch)) {
+		return false
+	}
+	if r.TmuxSession != "" && !glob(r.TmuxSession, s.fact(KeyTmuxSession)) {
+		return false
+	}
+	if r.TmuxWindow != "" && !glob(r.TmuxWindow, s.fact(KeyTmuxWindow)) {
+		return false
+	}
+	return true
+}
+
+// FanOut returns the chats other than primary that the fan-out routes copy
+// event to for s, a session carrying the RouteMap keys in keys.
+func FanOut(routes []config.FanOutRoute, s *Session, keys []string, event string, primary int64) []int64 {
+	var chats []int64
+	for _, f := range routes {
+		if f.Chat == 0 || f.Chat == primary || !f.Copies(event) || slices.Contains(chats, f.Chat) {
+			continue
+		}
+		if f.Key == "" && !hasConditions(f.RouteRule) {
+			continue
+		}
+		if f.Key != "" && !slices.Contains(keys, f.Key) {
+			continue
+		}
+		if hasConditions(f.RouteRule) && !Matches(f.RouteRule, s) {
+			continue
+		}
+		chats = append(chats, f.Chat)
+	}
+	return chats
+}
+
+func hasConditions(r config.RouteRule) bool {
+	return r.CWD != "" || r.Remote != "" || r.Branch != "" || r.TmuxSession != "" || r.TmuxWindow != "" || r.Env != ""
+}
+
+// Describe renders the conditions of r for display, e.g.
+// "cwd=~/work/* branch=release/*".
+func Describe(r config.RouteRule) string {
+	return describe(r.Name, conditions(r))
+}
+
+// DescribeFanOut renders the key and conditions of f for display, e.g.
+// "key=alias:api cwd=~/work".
+func DescribeFanOut(f config.FanOutRoute) string {
+	parts := conditions(f.RouteRule)
+	if f.Key != "" {
+		parts = append([]string{"key=" + f.Key}, parts...)
+	}
+	return describe(f.Name, parts)
+}
+
+func conditions(r config.RouteRule) []string {
+	var parts []string
+	add := func(key, v string) {
+		if v != "" {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	add("cwd", r.CWD)
+	add("remote", r.Remote)
+	add("branch", r.Branch)
+	add("session", r.TmuxSession)
+	add("window", r.TmuxWindow)
+	add("env", r.Env)
+	return parts
+}
+
+func describe(name string, parts []string) string {
+	desc := strings.Join(parts, " ")
+	if name != "" {
+		desc = fmt.Sprintf("%s (%s)", name, desc)
+	}
+	return desc
+}
+
+// matchDir matches cwd against a directory pattern. Without glob characters
+// the pattern is a prefix: the directory itself and everything below it.
+// With them, the pattern must match cwd or one of its parents, so
+// "~/work/*/api" also covers "~/work/shop/api/cmd".
+func matchDir(pattern, cwd string) bool {
+	if cwd == "" {
+		return false
+	}
+	pattern = filepath.Clean(expandHome(pattern))
+	cwd = filepath.Clean(cwd)
+	if !strings.ContainsAny(pattern, "*?") {
+		return cwd == pattern || strings.HasPrefix(cwd, strings.TrimSuffix(pattern, "/")+"/")
+	}
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		if glob(pattern, dir) {
+			return true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
+
+// matchRemote matches a remote URL as written or in its host/path form, so
+// "github.com/acme/*" matches both the SSH and the HTTPS URL of a repo.
+func matchRemote(pattern, url string) bool {
+	if url == "" {
+		return false
+	}
+	return glob(pattern, url) || glob(pattern, NormalizeRemote(url))
+}
+
+// NormalizeRemote reduces a git remote URL to "host/path" without scheme,
+// user and ".git" suffix.
+func NormalizeRemote(url string) string {
+	u := strings.TrimSpace(url)
+	if i := strings.Index(u, "://"); i != -1 {
+		u = u[i+3:]
+	} else if at := strings.Index(u, "@"); at != -1 {
+		// scp-like syntax: user@host:path
+		u = strings.Replace(u[at+1:], ":", "/", 1)
+	}
+	if at := strings.Index(u, "@"); at != -1 && at < strings.Index(u+"/", "/") {
+		u = u[at+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+}
+
+// glob matches s against pattern, where "*" matches any text (including
+// "/") and "?" any single character.
+func glob(pattern, s string) bool {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	return err == nil && re.MatchString(s)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
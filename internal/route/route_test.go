@@ -0,0 +1,174 @@
+package route
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Seraphli/tg-cli/internal/config"
+)
+
+func TestMatchDir(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	tests := []struct {
+		pattern, cwd string
+		want         bool
+	}{
+		{"/srv/app", "/srv/app", true},
+		{"/srv/app", "/srv/app/cmd", true},
+		{"/srv/app/", "/srv/app/cmd", true},
+		{"/srv/app", "/srv/application", false},
+		{"~/work", "/home/alice/work/shop", true},
+		{"~/work/*/api", "/home/alice/work/shop/api", true},
+		{"~/work/*/api", "/home/alice/work/shop/api/cmd", true},
+		{"~/work/*/api", "/home/alice/work/shop/web", false},
+		{"/srv/app", "", false},
+	}
+	for _, tt := range tests {
+		if got := matchDir(tt.pattern, tt.cwd); got != tt.want {
+			t.Errorf("matchDir(%q, %q) = %v, want %v", tt.pattern, tt.cwd, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeRemote(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:acme/shop.git":           "github.com/acme/shop",
+		"https://github.com/acme/shop.git":       "github.com/acme/shop",
+		"https://bob@gitlab.example.com/x/y":     "gitlab.example.com/x/y",
+		"ssh://git@github.com/acme/shop.git":     "github.com/acme/shop",
+		"/srv/git/shop.git":                      "/srv/git/shop",
+		"https://github.com/acme/shop/":          "github.com/acme/shop",
+		"ssh://git@host.example:2222/team/x.git": "host.example:2222/team/x",
+	}
+	for in, want := range tests {
+		if got := NormalizeRemote(in); got != want {
+			t.Errorf("NormalizeRemote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	rules := []config.RouteRule{
+		{Name: "empty", Chat: 1},
+		{Env: "ops", Chat: 2},
+		{Remote: "github.com/acme/*", Branch: "release/*", Chat: 3},
+		{TmuxSession: "scratch", Chat: 4},
+		{CWD: "~/work", Chat: 5},
+	}
+	lookups := 0
+	facts := map[string]string{
+		KeyRemote:      "git@github.com:acme/shop.git",
+		KeyBranch:      "release/1.2",
+		KeyTmuxSession: "main",
+	}
+	s := &Session{CWD: "/home/alice/work/shop", Lookup: func(key string) string {
+		lookups++
+		return facts[key]
+	}}
+	if i, ok := Match(rules, s); !ok || i != 2 {
+		t.Errorf("Match = %d, %v; want 2, true", i, ok)
+	}
+	if i, ok := Match(rules, s); !ok || i != 2 || lookups != 2 {
+		t.Errorf("second Match = %d, %v with %d lookups; want 2, true with 2", i, ok, lookups)
+	}
+
+	s = &Session{CWD: "/home/alice/work/shop", Env: "ops"}
+	if i, _ := Match(rules, s); i != 1 {
+		t.Errorf("env Match = %d, want 1", i)
+	}
+
+	facts[KeyBranch] = "main"
+	s = &Session{CWD: "/home/alice/work/shop", Lookup: func(key string) string { return facts[key] }}
+	if i, _ := Match(rules, s); i != 4 {
+		t.Errorf("cwd Match = %d, want 4", i)
+	}
+
+	s = &Session{CWD: "/tmp"}
+	if _, ok := Match(rules, s); ok {
+		t.Error("Match of unrouted session succeeded")
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	routes := []config.FanOutRoute{
+		{RouteRule: config.RouteRule{Chat: 10}},
+		{RouteRule: config.RouteRule{CWD: "~/work", Chat: 11}, Events: []string{"PermissionRequest", "AskUserQuestion"}},
+		{RouteRule: config.RouteRule{Chat: 12}, Key: "alias:api", Events: []string{"stop"}},
+		{RouteRule: config.RouteRule{CWD: "~/work", Chat: 13}, Key: "alias:web"},
+		{RouteRule: config.RouteRule{CWD: "~/work", Chat: 1}},
+		{RouteRule: config.RouteRule{Env: "*", Chat: 11}},
+	}
+	s := &Session{CWD: "/home/alice/work/shop", Env: "ops"}
+	keys := []string{"alias:api", "pane:main:1.0"}
+	tests := []struct {
+		event string
+		want  []int64
+	}{
+		{"PermissionRequest", []int64{11}},
+		{"Stop", []int64{12, 11}},
+		{"SessionStart", []int64{11}},
+	}
+	for _, tt := range tests {
+		got := FanOut(routes, s, keys, tt.event, 1)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("FanOut(%s) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	r := config.RouteRule{Name: "releases", CWD: "~/work/*", Branch: "release/*"}
+	if got, want := Describe(r), "releases (cwd=~/work/* branch=release/*)"; got != want {
+		t.Errorf("Describe = %q, want %q", got, want)
+	}
+	f := config.FanOutRoute{RouteRule: config.RouteRule{Name: "dm", CWD: "~/work"}, Key: "alias:api"}
+	if got, want := DescribeFanOut(f), "dm (key=alias:api cwd=~/work)"; got != want {
+		t.Errorf("DescribeFanOut = %q, want %q", got, want)
+	}
+}
+
+func TestIdentityKeys(t *testing.T) {
+	id := Identity{Alias: "api", ClaudeRoot: "0f3c9a2e-1111", Location: "main:1.0", Socket: "/tmp/s", PaneID: "%12"}
+	want := []string{"alias:api", "claude:0f3c9a2e-1111", "pane:main:1.0@/tmp/s", "%12@/tmp/s"}
+	got := id.Keys()
+	if len(got) != len(want) {
+		t.Fatalf("Keys = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Keys[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if k := id.PaneRouteKey(); k != "pane:main:1.0@/tmp/s" {
+		t.Errorf("PaneRouteKey = %q", k)
+	}
+	if k := id.SessionRouteKey(); k != "alias:api" {
+		t.Errorf("SessionRouteKey = %q, want alias:api", k)
+	}
+	bare := Identity{PaneID: "%3"}
+	if k := bare.PaneRouteKey(); k != "%3" {
+		t.Errorf("PaneRouteKey of pane ID only = %q, want %%3", k)
+	}
+	if k := bare.SessionRouteKey(); k != "" {
+		t.Errorf("SessionRouteKey of pane ID only = %q, want empty", k)
+	}
+	if loc, sock, ok := ParsePaneKey("pane:main:1.0@/tmp/s"); !ok || loc != "main:1.0" || sock != "/tmp/s" {
+		t.Errorf("ParsePaneKey = %q, %q, %v", loc, sock, ok)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := map[string]string{
+		"alias:api":                 "🏷 api",
+		"claude:0f3c9a2e-1111-2222": "🔗 0f3c9a2e",
+		"pane:main:1.0@/tmp/s":      "📟 main:1.0",
+		"%12@/tmp/s":                "📟 %12",
+	}
+	for key, want := range tests {
+		if got := Label(key); got != want {
+			t.Errorf("Label(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package usage keeps per-session, per-day token and cost totals.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retainDays is how long daily entries are kept.
+const retainDays = 120
+
+// Tokens counts the tokens of one or more API responses.
+type Tokens struct {
+	Input         int64 `json:"input"`
+	Output        int64 `json:"output"`
+	CacheCreation int64 `json:"cacheCreation"`
+	CacheRead     int64 `json:"cacheRead"`
+}
+
+func (t *Tokens) Add(o Tokens) {
+	t.Input += o.Input
+	t.Output += o.Output
+	t.CacheCreation += o.CacheCreation
+	t.CacheRead += o.CacheRead
+}
+
+// Total returns all tokens, cache included.
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output + t.CacheCreation + t.CacheRead
+}
+
+// Entry is the usage of one session on one day.
+type Entry struct {
+	Date      string `json:"date"` // YYYY-MM-DD, local time
+	SessionID string `json:"sessionId"`
+	Project   string `json:"project"` // session working directory
+	Tokens
+	CostUSD float64 `json:"costUsd,omitempty"`
+}
+
+// Totals is the usage summed over a period, overall or for one project.
+type Totals struct {
+	Project  string
+	Sessions int
+	Tokens
+	CostUSD float64
+}
+
+type storeData struct {
+	Entries    []Entry            `json:"entries"`
+	LastCost   map[string]float64 `json:"lastCost"`             // sessionID → cumulative cost last seen
+	LastReport string             `json:"lastReport,omitempty"` // date of the last daily report
+}
+
+// Store persists usage entries to a JSON file.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data storeData
+}
+
+// Open loads the store at path; a missing or unreadable file starts empty.
+func Open(path string) *Store {
+	s := &Store{path: path}
+	if raw, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(raw, &s.data)
+	}
+	if s.data.LastCost == nil {
+		s.data.LastCost = make(map[string]float64)
+	}
+	return s
+}
+
+// Day formats t as a store date.
+func Day(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+func (s *Store) entry(date, sessionID, project string) *Entry {
+	for i := range s.data.Entries {
+		e := &s.data.Entries[i]
+		if e.Date == date && e.SessionID == sessionID {
+			if project != "" {
+				e.Project = project
+			}
+			return e
+		}
+	}
+	s.data.Entries = append(s.data.Entries, Entry{Date: date, SessionID: sessionID, Project: project})
+	return &s.data.Entries[len(s.data.Entries)-1]
+}
+
+// SetTokens replaces a session's token counts for the given days. Counts come
+// from re-scanning the whole transcript, so setting them is idempotent.
+func (s *Store) SetTokens(sessionID, project string, perDay map[string]Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for date, t := range perDay {
+		s.entry(date, sessionID, project).Tokens = t
+	}
+	return s.save()
+}
+
+// AddCost records a session's cumulative cost, attributing the increase since
+// the last call to date.
+func (s *Store) AddCost(sessionID, project, date string, total float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delta := total - s.data.LastCost[sessionID]
+	if delta < 0 {
+		// Counter restarted (e.g. the session was resumed in a new process)
+		delta = total
+	}
+	s.data.LastCost[sessionID] = total
+	if delta > 0 {
+		s.entry(date, sessionID, project).CostUSD += delta
+	}
+	return s.save()
+}
+
+// Summarize sums entries dated from..to inclusive. It returns the overall
+// totals and per-project totals ordered by cost, then tokens.
+func (s *Store) Summarize(from, to string) (Totals, []Totals) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total Totals
+	byProject := make(map[string]*Totals)
+	sessions := make(map[string]bool)
+	projectSessions := make(map[string]map[string]bool)
+	for _, e := range s.data.Entries {
+		if e.Date < from || e.Date > to {
+			continue
+		}
+		p := byProject[e.Project]
+		if p == nil {
+			p = &Totals{Project: e.Project}
+			byProject[e.Project] = p
+			projectSessions[e.Project] = make(map[string]bool)
+		}
+		p.Tokens.Add(e.Tokens)
+		p.CostUSD += e.CostUSD
+		total.Tokens.Add(e.Tokens)
+		total.CostUSD += e.CostUSD
+		sid := baseSession(e.SessionID)
+		sessions[sid] = true
+		projectSessions[e.Project][sid] = true
+	}
+	total.Sessions = len(sessions)
+	var projects []Totals
+	for name, p := range byProject {
+		p.Sessions = len(projectSessions[name])
+		projects = append(projects, *p)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].CostUSD != projects[j].CostUSD {
+			return projects[i].CostUSD > projects[j].CostUSD
+		}
+		if projects[i].Total() != projects[j].Total() {
+			return projects[i].Total() > projects[j].Total()
+		}
+		return projects[i].Project < projects[j].Project
+	})
+	return total, projects
+}
+
+// baseSession strips the "/<agent>" suffix used for subagent transcripts.
+func baseSession(id string) string {
+	base, _, _ := strings.Cut(id, "/")
+	return base
+}
+
+// LastReport returns the date of the last daily report.
+func (s *Store) LastReport() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.LastReport
+}
+
+// SetLastReport records that the daily report for date was sent.
+func (s *Store) SetLastReport(date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.LastReport = date
+	return s.save()
+}
+
+func (s *Store) save() error {
+	cutoff := Day(time.Now().AddDate(0, 0, -retainDays))
+	kept := s.data.Entries[:0]
+	for _, e := range s.data.Entries {
+		if e.Date >= cutoff {
+			kept = append(kept, e)
+		}
+	}
+	s.data.Entries = kept
+	raw, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// ScanTranscript sums the token usage of assistant messages in a Claude Code
+// transcript per local day. Messages are counted once even when their content
+// blocks span several lines.
+func ScanTranscript(r io.Reader) map[string]Tokens {
+	type msgUsage struct {
+		date   string
+		tokens Tokens
+	}
+	byID := make(map[string]msgUsage)
+	var order []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for sc.Scan() {
+		var line struct {
+			Type      string    `json:"type"`
+			Timestamp time.Time `json:"timestamp"`
+			RequestID string    `json:"requestId"`
+			Message   struct {
+				ID    string `json:"id"`
+				Usage *struct {
+					Input         int64 `json:"input_tokens"`
+					Output        int64 `json:"output_tokens"`
+					CacheCreation int64 `json:"cache_creation_input_tokens"`
+					CacheRead     int64 `json:"cache_read_input_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(sc.Bytes(), &line) != nil || line.Type != "assistant" || line.Message.Usage == nil {
+			continue
+		}
+		id := line.Message.ID
+		if id == "" {
+			id = line.RequestID
+		}
+		if id == "" {
+			id = "line-" + strconv.Itoa(len(order))
+		}
+		if _, seen := byID[id]; !seen {
+			order = append(order, id)
+		}
+		u := line.Message.Usage
+		byID[id] = msgUsage{
+			date:   Day(line.Timestamp),
+			tokens: Tokens{Input: u.Input, Output: u.Output, CacheCreation: u.CacheCreation, CacheRead: u.CacheRead},
+		}
+	}
+	perDay := make(map[string]Tokens)
+	for _, id := range order {
+		m := byID[id]
+		t := perDay[m.date]
+		t.Add(m.tokens)
+		perDay[m.date] = t
+	}
+	return perDay
+}
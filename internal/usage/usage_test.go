@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanTranscriptDedupesMessages(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local).Format(time.RFC3339)
+	transcript := strings.Join([]string{
+		`{"type":"user","timestamp":"` + ts + `","message":{"content":"hi"}}`,
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":100}}}`,
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":100}}}`,
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m2","usage":{"input_tokens":3,"output_tokens":7,"cache_creation_input_tokens":20}}}`,
+		`not json`,
+	}, "\n")
+	got := ScanTranscript(strings.NewReader(transcript))
+	want := Tokens{Input: 13, Output: 12, CacheCreation: 20, CacheRead: 100}
+	if len(got) != 1 || got["2026-03-04"] != want {
+		t.Fatalf("ScanTranscript = %+v, want %+v on 2026-03-04", got, want)
+	}
+}
+
+func TestStoreSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	s := Open(path)
+	today := Day(time.Now())
+	yesterday := Day(time.Now().AddDate(0, 0, -1))
+	s.SetTokens("a", "/p/one", map[string]Tokens{today: {Input: 10, Output: 5}, yesterday: {Input: 1}})
+	s.SetTokens("a", "/p/one", map[string]Tokens{today: {Input: 20, Output: 5}})
+	s.SetTokens("a/agent1", "/p/one", map[string]Tokens{today: {Output: 3}})
+	s.SetTokens("b", "/p/two", map[string]Tokens{today: {Input: 1}})
+	s.AddCost("b", "/p/two", today, 0.5)
+	s.AddCost("b", "/p/two", today, 0.75)
+
+	total, projects := Open(path).Summarize(today, today)
+	if total.Input != 21 || total.Output != 8 || total.Sessions != 2 {
+		t.Errorf("total = %+v", total)
+	}
+	if total.CostUSD != 0.75 {
+		t.Errorf("total cost = %v, want 0.75", total.CostUSD)
+	}
+	if len(projects) != 2 || projects[0].Project != "/p/two" || projects[1].Sessions != 1 {
+		t.Errorf("projects = %+v", projects)
+	}
+
+	total, _ = s.Summarize(yesterday, today)
+	if total.Input != 22 {
+		t.Errorf("two-day input = %d, want 22", total.Input)
+	}
+}
+
+func TestAddCostRestart(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "usage.json"))
+	day := Day(time.Now())
+	s.AddCost("a", "/p", day, 2)
+	s.AddCost("a", "/p", day, 0.5)
+	total, _ := s.Summarize(day, day)
+	if total.CostUSD != 2.5 {
+		t.Errorf("cost = %v, want 2.5", total.CostUSD)
+	}
+}